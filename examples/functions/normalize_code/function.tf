@@ -0,0 +1,3 @@
+output "normalized" {
+  value = provider::nanoid::normalize_code("7kq4-m9xd-2hpr")
+}
@@ -0,0 +1,3 @@
+output "numbers" {
+  value = provider::nanoid::sqids_decode(nanoid_sqid.this.sqid, nanoid_sqid.this.shuffled_alphabet)
+}
@@ -0,0 +1,3 @@
+output "sqid" {
+  value = provider::nanoid::sqids_encode([1, 2, 3], nanoid_sqid.this.shuffled_alphabet, 8)
+}
@@ -0,0 +1,7 @@
+resource "nanoid_id" "this" {
+  checksum = "damm"
+}
+
+output "valid" {
+  value = provider::nanoid::verify_checksum(nanoid_id.this.id, nanoid_id.this.alphabet, "damm")
+}
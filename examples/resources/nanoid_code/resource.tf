@@ -0,0 +1,5 @@
+resource "nanoid_code" "this" {
+  group_size = 4
+  groups     = 3
+  separator  = "-"
+}
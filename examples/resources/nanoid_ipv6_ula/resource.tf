@@ -0,0 +1,3 @@
+resource "nanoid_ipv6_ula" "this" {
+  subnet_count = 4
+}
@@ -0,0 +1,5 @@
+resource "nanoid_mac_address" "this" {
+  pool   = "lab"
+  prefix = "02:42"
+  format = "hyphen"
+}
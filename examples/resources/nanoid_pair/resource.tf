@@ -0,0 +1,3 @@
+resource "nanoid_pair" "this" {
+  flip = "blue"
+}
@@ -0,0 +1,6 @@
+resource "nanoid_port" "this" {
+  pool    = "sidecars"
+  min     = 20000
+  max     = 29999
+  exclude = [22222]
+}
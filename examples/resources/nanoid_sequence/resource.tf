@@ -0,0 +1,4 @@
+resource "nanoid_sequence" "this" {
+  format = "env-{seq:04}-{rand:4}"
+  keys   = ["api", "worker"]
+}
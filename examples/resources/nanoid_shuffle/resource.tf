@@ -0,0 +1,4 @@
+resource "nanoid_shuffle" "this" {
+  input        = ["eu-west-1a", "eu-west-1b", "eu-west-1c"]
+  result_count = 2
+}
@@ -0,0 +1,4 @@
+resource "nanoid_sqid" "this" {
+  numbers    = [42]
+  min_length = 8
+}
@@ -0,0 +1,316 @@
+// Copyright (c) The Nanoid Provider for Terraform Authors
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"fmt"
+	"math/bits"
+)
+
+const CHECKSUM_LUHN = "luhn"
+const CHECKSUM_DAMM = "damm"
+const CHECKSUM_VERHOEFF = "verhoeff"
+const CHECKSUM_ISO7064 = "iso7064"
+
+var CHECKSUM_ALGORITHMS = []string{CHECKSUM_LUHN, CHECKSUM_DAMM, CHECKSUM_VERHOEFF, CHECKSUM_ISO7064}
+
+// checksumAlphabet maps the characters of an alphabet to their position.
+type checksumAlphabet struct {
+	runes   []rune
+	indexes map[rune]int
+}
+
+func newChecksumAlphabet(alphabet string) (*checksumAlphabet, error) {
+	runes := []rune(alphabet)
+	if len(runes) < 2 {
+		return nil, fmt.Errorf("the alphabet must contain at least 2 characters")
+	}
+
+	indexes := make(map[rune]int, len(runes))
+	for i, r := range runes {
+		if _, ok := indexes[r]; ok {
+			return nil, fmt.Errorf("the alphabet contains the character %q more than once", r)
+		}
+		indexes[r] = i
+	}
+
+	return &checksumAlphabet{runes: runes, indexes: indexes}, nil
+}
+
+// values converts the input to alphabet positions.
+func (a *checksumAlphabet) values(input string) ([]int, error) {
+	runes := []rune(input)
+	values := make([]int, len(runes))
+	for i, r := range runes {
+		v, ok := a.indexes[r]
+		if !ok {
+			return nil, fmt.Errorf("the character %q at position %d is not part of the alphabet", r, i)
+		}
+		values[i] = v
+	}
+
+	return values, nil
+}
+
+// validateChecksumAlphabet reports whether the algorithm supports the alphabet.
+func validateChecksumAlphabet(algorithm string, alphabet string) error {
+	a, err := newChecksumAlphabet(alphabet)
+	if err != nil {
+		return err
+	}
+
+	switch algorithm {
+	case CHECKSUM_DAMM:
+		_, err = newDammQuasigroup(len(a.runes))
+	case CHECKSUM_VERHOEFF:
+		err = verhoeffAlphabet(len(a.runes))
+	}
+
+	return err
+}
+
+// computeChecksum returns the check character of the input for the given algorithm.
+func computeChecksum(algorithm string, alphabet string, input string) (string, error) {
+	a, err := newChecksumAlphabet(alphabet)
+	if err != nil {
+		return "", err
+	}
+
+	values, err := a.values(input)
+	if err != nil {
+		return "", err
+	}
+
+	n := len(a.runes)
+	var check int
+	switch algorithm {
+	case CHECKSUM_LUHN:
+		check = luhnCheck(n, values)
+	case CHECKSUM_DAMM:
+		q, err := newDammQuasigroup(n)
+		if err != nil {
+			return "", err
+		}
+		check = q.check(values)
+	case CHECKSUM_VERHOEFF:
+		if err := verhoeffAlphabet(n); err != nil {
+			return "", err
+		}
+		check = verhoeffCheck(values)
+	case CHECKSUM_ISO7064:
+		check = iso7064Check(n, values)
+	default:
+		return "", fmt.Errorf("unknown checksum algorithm %q", algorithm)
+	}
+
+	return string(a.runes[check]), nil
+}
+
+// verifyChecksum reports whether the last character of the input is the
+// valid check character of the characters preceding it.
+func verifyChecksum(algorithm string, alphabet string, input string) (bool, error) {
+	runes := []rune(input)
+	if len(runes) < 2 {
+		return false, nil
+	}
+
+	a, err := newChecksumAlphabet(alphabet)
+	if err != nil {
+		return false, err
+	}
+
+	// Characters outside of the alphabet can never produce a valid checksum.
+	if _, err := a.values(input); err != nil {
+		return false, nil
+	}
+
+	check, err := computeChecksum(algorithm, alphabet, string(runes[:len(runes)-1]))
+	if err != nil {
+		return false, err
+	}
+
+	return check == string(runes[len(runes)-1]), nil
+}
+
+// luhnCheck implements the Luhn mod N algorithm.
+func luhnCheck(n int, values []int) int {
+	factor := 2
+	sum := 0
+	for i := len(values) - 1; i >= 0; i-- {
+		addend := factor * values[i]
+		sum += addend/n + addend%n
+		if factor == 2 {
+			factor = 1
+		} else {
+			factor = 2
+		}
+	}
+
+	return (n - sum%n) % n
+}
+
+// iso7064Check implements the ISO/IEC 7064 hybrid system MOD N+1,N.
+func iso7064Check(n int, values []int) int {
+	p := n
+	for _, v := range values {
+		s := (p + v) % n
+		if s == 0 {
+			s = n
+		}
+		p = (2 * s) % (n + 1)
+	}
+
+	return (n + 1 - p) % n
+}
+
+var verhoeffMultiplication = [10][10]int{
+	{0, 1, 2, 3, 4, 5, 6, 7, 8, 9},
+	{1, 2, 3, 4, 0, 6, 7, 8, 9, 5},
+	{2, 3, 4, 0, 1, 7, 8, 9, 5, 6},
+	{3, 4, 0, 1, 2, 8, 9, 5, 6, 7},
+	{4, 0, 1, 2, 3, 9, 5, 6, 7, 8},
+	{5, 9, 8, 7, 6, 0, 4, 3, 2, 1},
+	{6, 5, 9, 8, 7, 1, 0, 4, 3, 2},
+	{7, 6, 5, 9, 8, 2, 1, 0, 4, 3},
+	{8, 7, 6, 5, 9, 3, 2, 1, 0, 4},
+	{9, 8, 7, 6, 5, 4, 3, 2, 1, 0},
+}
+
+var verhoeffPermutation = [8][10]int{
+	{0, 1, 2, 3, 4, 5, 6, 7, 8, 9},
+	{1, 5, 7, 6, 2, 8, 3, 0, 9, 4},
+	{5, 8, 0, 3, 7, 9, 6, 1, 4, 2},
+	{8, 9, 1, 6, 0, 4, 3, 5, 2, 7},
+	{9, 4, 5, 3, 1, 2, 6, 8, 7, 0},
+	{4, 2, 8, 6, 5, 7, 3, 9, 0, 1},
+	{2, 7, 9, 3, 8, 0, 6, 4, 1, 5},
+	{7, 0, 4, 6, 9, 1, 3, 2, 5, 8},
+}
+
+var verhoeffInverse = [10]int{0, 4, 3, 2, 1, 5, 6, 7, 8, 9}
+
+func verhoeffAlphabet(n int) error {
+	if n != 10 {
+		return fmt.Errorf("the verhoeff algorithm requires an alphabet of exactly 10 characters, got %d", n)
+	}
+
+	return nil
+}
+
+// verhoeffCheck implements the Verhoeff algorithm over the dihedral group D5.
+func verhoeffCheck(values []int) int {
+	c := 0
+	for i := range values {
+		c = verhoeffMultiplication[c][verhoeffPermutation[(i+1)%8][values[len(values)-1-i]]]
+	}
+
+	return verhoeffInverse[c]
+}
+
+var dammDecimal = [10][10]int{
+	{0, 3, 1, 7, 5, 9, 8, 6, 4, 2},
+	{7, 0, 9, 2, 1, 5, 4, 8, 6, 3},
+	{4, 2, 0, 6, 8, 7, 1, 3, 5, 9},
+	{1, 7, 5, 0, 9, 8, 3, 4, 2, 6},
+	{6, 1, 2, 3, 0, 4, 5, 9, 7, 8},
+	{3, 6, 7, 4, 2, 0, 9, 5, 8, 1},
+	{5, 8, 6, 9, 7, 2, 0, 1, 3, 4},
+	{8, 9, 4, 5, 3, 6, 2, 0, 1, 7},
+	{9, 4, 3, 8, 6, 1, 7, 2, 0, 5},
+	{2, 5, 8, 1, 4, 3, 6, 7, 9, 0},
+}
+
+// gf2Polynomials are irreducible polynomials used to build GF(2^k).
+var gf2Polynomials = map[int]int{
+	2:  0b111,
+	3:  0b1011,
+	4:  0b10011,
+	5:  0b100101,
+	6:  0b1000011,
+	7:  0b10000011,
+	8:  0b100011011,
+	9:  0b1000010001,
+	10: 0b10000001001,
+	11: 0b100000000101,
+	12: 0b1000001010011,
+	13: 0b10000000011011,
+	14: 0b100010001000011,
+	15: 0b1000000000000011,
+	16: 0b10001000000001011,
+}
+
+// dammQuasigroup is a totally anti-symmetric quasigroup of order n.
+//
+// Decimal alphabets use the table published by H. Michael Damm. Other
+// orders are built as the direct product of x*y = 2x+y over GF(2^k), for the
+// power of two part of n, and x*y = 2x+y mod q, for the odd part q of n.
+// Such a quasigroup cannot be built this way when n is congruent to 2
+// modulo 4.
+type dammQuasigroup struct {
+	n int
+	// k is the exponent of the power of two part of n, q its odd part.
+	k int
+	q int
+}
+
+func newDammQuasigroup(n int) (*dammQuasigroup, error) {
+	if n == 10 {
+		return &dammQuasigroup{n: n}, nil
+	}
+
+	k := bits.TrailingZeros(uint(n))
+	if _, ok := gf2Polynomials[k]; k == 1 || (k > 1 && !ok) {
+		return nil, fmt.Errorf("the damm algorithm does not support alphabets of %d characters, the size must be 10 or not congruent to 2 modulo 4", n)
+	}
+
+	return &dammQuasigroup{n: n, k: k, q: n >> k}, nil
+}
+
+// op returns x*y, computed rather than tabulated to support large alphabets.
+func (d *dammQuasigroup) op(x int, y int) int {
+	if d.n == 10 {
+		return dammDecimal[x][y]
+	}
+
+	hi := gf2Multiply(d.k, 2, x/d.q) ^ (y / d.q)
+	lo := (2*(x%d.q) + y%d.q) % d.q
+	return hi*d.q + lo
+}
+
+// gf2Multiply multiplies a and b in GF(2^k).
+func gf2Multiply(k int, a int, b int) int {
+	if k == 0 {
+		return 0
+	}
+
+	result := 0
+	for b > 0 {
+		if b&1 == 1 {
+			result ^= a
+		}
+		b >>= 1
+		a <<= 1
+		if a&(1<<k) != 0 {
+			a ^= gf2Polynomials[k]
+		}
+	}
+
+	return result
+}
+
+func (d *dammQuasigroup) check(values []int) int {
+	interim := 0
+	for _, v := range values {
+		interim = d.op(interim, v)
+	}
+
+	// The check character is the one bringing the interim value back to 0.
+	for c := 0; c < d.n; c++ {
+		if d.op(interim, c) == 0 {
+			return c
+		}
+	}
+
+	return 0
+}
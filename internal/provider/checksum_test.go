@@ -0,0 +1,179 @@
+// Copyright (c) The Nanoid Provider for Terraform Authors
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"math/bits"
+	"testing"
+)
+
+func TestComputeChecksum_KnownValues(t *testing.T) {
+	cases := []struct {
+		algorithm string
+		input     string
+		expected  string
+	}{
+		{CHECKSUM_LUHN, "7992739871", "3"},
+		{CHECKSUM_DAMM, "572", "4"},
+		{CHECKSUM_VERHOEFF, "236", "3"},
+		{CHECKSUM_VERHOEFF, "12345", "1"},
+		{CHECKSUM_ISO7064, "0794", "5"},
+	}
+
+	for _, c := range cases {
+		check, err := computeChecksum(c.algorithm, "0123456789", c.input)
+		if err != nil {
+			t.Fatalf("%s(%q): unexpected error: %s", c.algorithm, c.input, err)
+		}
+		if check != c.expected {
+			t.Errorf("%s(%q): expected %q, got %q", c.algorithm, c.input, c.expected, check)
+		}
+	}
+}
+
+func TestVerifyChecksum_DetectsSubstitutions(t *testing.T) {
+	alphabets := map[string][]string{
+		CHECKSUM_LUHN:     {"0123456789", DEFAULT_ID_ALPHABET, DEFAULT_DNS_ALPHABET, "ab"},
+		CHECKSUM_DAMM:     {"0123456789", DEFAULT_ID_ALPHABET, DEFAULT_DNS_ALPHABET, "abc", "abcd", "abcdefghijkl"},
+		CHECKSUM_VERHOEFF: {"0123456789", "abcdefghij"},
+		CHECKSUM_ISO7064:  {"0123456789", DEFAULT_ID_ALPHABET, DEFAULT_DNS_ALPHABET, "ab"},
+	}
+
+	for algorithm, list := range alphabets {
+		for _, alphabet := range list {
+			runes := []rune(alphabet)
+			input := string([]rune{runes[1], runes[0], runes[len(runes)-1], runes[1]})
+			check, err := computeChecksum(algorithm, alphabet, input)
+			if err != nil {
+				t.Fatalf("%s over %q: unexpected error: %s", algorithm, alphabet, err)
+			}
+
+			id := []rune(input + check)
+			valid, err := verifyChecksum(algorithm, alphabet, string(id))
+			if err != nil || !valid {
+				t.Fatalf("%s over %q: expected %q to be valid", algorithm, alphabet, string(id))
+			}
+
+			for i := range id {
+				for _, r := range runes {
+					if r == id[i] {
+						continue
+					}
+					mutated := append([]rune{}, id...)
+					mutated[i] = r
+					if valid, _ := verifyChecksum(algorithm, alphabet, string(mutated)); valid {
+						t.Errorf("%s over %q: substitution %q was not detected", algorithm, alphabet, string(mutated))
+					}
+				}
+			}
+		}
+	}
+}
+
+func TestComputeChecksum_Errors(t *testing.T) {
+	cases := []struct {
+		algorithm string
+		alphabet  string
+		input     string
+	}{
+		{CHECKSUM_LUHN, "a", "a"},
+		{CHECKSUM_LUHN, "aab", "ab"},
+		{CHECKSUM_LUHN, "ab", "abc"},
+		{CHECKSUM_DAMM, "abcdef", "abc"},
+		{CHECKSUM_VERHOEFF, DEFAULT_ID_ALPHABET, "abc"},
+		{"crc32", "0123456789", "123"},
+	}
+
+	for _, c := range cases {
+		if _, err := computeChecksum(c.algorithm, c.alphabet, c.input); err == nil {
+			t.Errorf("%s over %q with input %q: expected an error", c.algorithm, c.alphabet, c.input)
+		}
+	}
+}
+
+func TestDammQuasigroup_TotallyAntiSymmetric(t *testing.T) {
+	for n := 3; n <= 255; n++ {
+		q, err := newDammQuasigroup(n)
+		if n%4 == 2 && n != 10 {
+			if err == nil {
+				t.Errorf("order %d: expected an error", n)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("order %d: unexpected error: %s", n, err)
+		}
+
+		table := make([][]int, n)
+		for x := range table {
+			table[x] = make([]int, n)
+			for y := range table[x] {
+				table[x][y] = q.op(x, y)
+			}
+		}
+
+		for c := 0; c < n; c++ {
+			for x := 0; x < n; x++ {
+				for y := x + 1; y < n; y++ {
+					if table[table[c][x]][y] == table[table[c][y]][x] {
+						t.Fatalf("order %d: (%d*%d)*%d == (%d*%d)*%d", n, c, x, y, c, y, x)
+					}
+				}
+			}
+		}
+	}
+}
+
+func TestGf2Polynomials_Irreducible(t *testing.T) {
+	for k, polynomial := range gf2Polynomials {
+		if bits.Len(uint(polynomial)) != k+1 {
+			t.Errorf("GF(2^%d): the polynomial %b is not of degree %d", k, polynomial, k)
+		}
+		for divisor := 2; bits.Len(uint(divisor))-1 <= k/2; divisor++ {
+			if gf2Remainder(polynomial, divisor) == 0 {
+				t.Errorf("GF(2^%d): the polynomial %b is divisible by %b", k, polynomial, divisor)
+			}
+		}
+	}
+}
+
+func TestValidateChecksumAlphabet(t *testing.T) {
+	cases := []struct {
+		algorithm string
+		size      int
+		valid     bool
+	}{
+		{CHECKSUM_DAMM, 10, true},
+		{CHECKSUM_DAMM, 6, false},
+		{CHECKSUM_DAMM, 1024, true},
+		{CHECKSUM_DAMM, MAX_ID_ALPHABET_LENGTH, true},
+		{CHECKSUM_VERHOEFF, 10, true},
+		{CHECKSUM_VERHOEFF, 64, false},
+		{CHECKSUM_LUHN, 1, false},
+		{CHECKSUM_ISO7064, 6, true},
+	}
+
+	for _, c := range cases {
+		runes := make([]rune, c.size)
+		for i := range runes {
+			runes[i] = rune(0x10000 + i)
+		}
+		err := validateChecksumAlphabet(c.algorithm, string(runes))
+		if c.valid && err != nil {
+			t.Errorf("%s over %d characters: unexpected error: %s", c.algorithm, c.size, err)
+		}
+		if !c.valid && err == nil {
+			t.Errorf("%s over %d characters: expected an error", c.algorithm, c.size)
+		}
+	}
+}
+
+// gf2Remainder returns the remainder of the division of two polynomials over GF(2).
+func gf2Remainder(a int, b int) int {
+	for bits.Len(uint(a)) >= bits.Len(uint(b)) {
+		a ^= b << (bits.Len(uint(a)) - bits.Len(uint(b)))
+	}
+
+	return a
+}
@@ -0,0 +1,56 @@
+// Copyright (c) The Nanoid Provider for Terraform Authors
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"regexp"
+	"strings"
+)
+
+// DEFAULT_ID_GROUP_SEPARATOR is not a character of DEFAULT_ID_ALPHABET, so
+// that grouping the ids of the default alphabet only needs group_size.
+const DEFAULT_ID_GROUP_SEPARATOR = "."
+
+// ID_FORMAT_PLACEHOLDERS are the placeholders of the format of a nanoid_id
+// resource, each written between braces.
+var ID_FORMAT_PLACEHOLDERS = []string{"id", "prefix", "suffix"}
+
+var formatPlaceholderRegexp = regexp.MustCompile(`\{([^{}]*)\}`)
+
+// groupId splits the characters of an id into groups of the given size, the
+// last one possibly shorter, joined by the separator.
+func groupId(id string, size int, separator string) string {
+	runes := []rune(id)
+	if size < 1 || len(runes) <= size {
+		return id
+	}
+
+	groups := make([]string, 0, (len(runes)+size-1)/size)
+	for i := 0; i < len(runes); i += size {
+		groups = append(groups, string(runes[i:min(i+size, len(runes))]))
+	}
+
+	return strings.Join(groups, separator)
+}
+
+// renderFormat replaces the placeholders of the format with their values,
+// leaving unknown placeholders as they are.
+func renderFormat(format string, values map[string]string) string {
+	return formatPlaceholderRegexp.ReplaceAllStringFunc(format, func(placeholder string) string {
+		if value, ok := values[placeholder[1:len(placeholder)-1]]; ok {
+			return value
+		}
+		return placeholder
+	})
+}
+
+// formatPlaceholders returns the names of the placeholders of the format.
+func formatPlaceholders(format string) []string {
+	var names []string
+	for _, match := range formatPlaceholderRegexp.FindAllStringSubmatch(format, -1) {
+		names = append(names, match[1])
+	}
+
+	return names
+}
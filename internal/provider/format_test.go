@@ -0,0 +1,50 @@
+// Copyright (c) The Nanoid Provider for Terraform Authors
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestGroupId(t *testing.T) {
+	cases := []struct {
+		id        string
+		size      int
+		separator string
+		expected  string
+	}{
+		{"ABCDEFGHIJKL", 4, "-", "ABCD-EFGH-IJKL"},
+		{"ABCDEFGHIJ", 4, " ", "ABCD EFGH IJ"},
+		{"ABCD", 4, "-", "ABCD"},
+		{"ABCD", 0, "-", "ABCD"},
+		{"日月火水木", 2, "·", "日月·火水·木"},
+	}
+
+	for _, c := range cases {
+		if grouped := groupId(c.id, c.size, c.separator); grouped != c.expected {
+			t.Errorf("groupId(%q, %d, %q): expected %q, got %q", c.id, c.size, c.separator, c.expected, grouped)
+		}
+	}
+}
+
+func TestRenderFormat(t *testing.T) {
+	values := map[string]string{"id": "ABCD-EFGH", "prefix": "INV", "suffix": "2026"}
+	cases := map[string]string{
+		"{prefix}-{id}-{suffix}": "INV-ABCD-EFGH-2026",
+		"#{id}":                  "#ABCD-EFGH",
+		"{id} {other}":           "ABCD-EFGH {other}",
+		"{{id}}":                 "{ABCD-EFGH}",
+	}
+
+	for format, expected := range cases {
+		if rendered := renderFormat(format, values); rendered != expected {
+			t.Errorf("renderFormat(%q): expected %q, got %q", format, expected, rendered)
+		}
+	}
+
+	if names := formatPlaceholders("{prefix}-{id}-{}"); !slices.Equal(names, []string{"prefix", "id", ""}) {
+		t.Errorf("unexpected placeholders %q", names)
+	}
+}
@@ -0,0 +1,88 @@
+// Copyright (c) The Nanoid Provider for Terraform Authors
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/function"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ function.Function = &NormalizeCodeFunction{}
+
+func NewNormalizeCodeFunction() function.Function {
+	return &NormalizeCodeFunction{}
+}
+
+// NormalizeCodeFunction defines the function implementation.
+type NormalizeCodeFunction struct{}
+
+func (f *NormalizeCodeFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "normalize_code"
+}
+
+func (f *NormalizeCodeFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary: "Normalize a code typed by a human",
+		MarkdownDescription: "Returns the canonical form of a code, as generated by the `nanoid_code` resource.\n\n" +
+			"Separators and whitespace are removed and letters missing from the alphabet are read in the other case. " +
+			"`I` and `L` are read as `1` and `O` is read as `0` when the alphabet does not contain them, as in the default Crockford base32 alphabet. " +
+			"The result can be compared with the `normalized` attribute of the `nanoid_code` resource.\n\n" +
+			"The alphabet and separator of the resource may be passed after the code, " +
+			fmt.Sprintf("they default to `%q` and `%q`: `normalize_code(code, alphabet, separator)`.", DEFAULT_CODE_ALPHABET, DEFAULT_CODE_SEPARATOR),
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:                "code",
+				MarkdownDescription: "The code to normalize.",
+			},
+		},
+		VariadicParameter: function.StringParameter{
+			Name:                "options",
+			MarkdownDescription: "The alphabet and then the separator of the code, at most two values.",
+		},
+		Return: function.StringReturn{},
+	}
+}
+
+func (f *NormalizeCodeFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var code string
+	var options []string
+	resp.Error = function.ConcatFuncErrors(resp.Error, req.Arguments.Get(ctx, &code, &options))
+	if resp.Error != nil {
+		return
+	}
+
+	if len(options) > 2 {
+		resp.Error = function.ConcatFuncErrors(resp.Error, function.NewArgumentFuncError(3, "Failed to normalize code: expected at most an alphabet and a separator."))
+		return
+	}
+
+	alphabet := DEFAULT_CODE_ALPHABET
+	if len(options) > 0 {
+		alphabet = options[0]
+	}
+	if alphabet == "" {
+		resp.Error = function.ConcatFuncErrors(resp.Error, function.NewArgumentFuncError(1, "Failed to normalize code: the alphabet must not be empty."))
+		return
+	}
+
+	separator := DEFAULT_CODE_SEPARATOR
+	if len(options) > 1 {
+		separator = options[1]
+	}
+	if err := validateCodeSeparator(alphabet, separator); err != nil {
+		resp.Error = function.ConcatFuncErrors(resp.Error, function.NewArgumentFuncError(2, fmt.Sprintf("Failed to normalize code: the %s.", err)))
+		return
+	}
+
+	normalized, err := normalizeCode(code, alphabet, separator)
+	if err != nil {
+		resp.Error = function.ConcatFuncErrors(resp.Error, function.NewArgumentFuncError(0, fmt.Sprintf("Failed to normalize code: %s.", err)))
+		return
+	}
+
+	resp.Error = function.ConcatFuncErrors(resp.Error, resp.Result.Set(ctx, normalized))
+}
@@ -0,0 +1,121 @@
+// Copyright (c) The Nanoid Provider for Terraform Authors
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/tfversion"
+)
+
+func TestAccNormalizeCodeFunction(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		TerraformVersionChecks: []tfversion.TerraformVersionCheck{
+			tfversion.SkipBelow(tfversion.Version1_8_0),
+		},
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+output "test" {
+  value = provider::nanoid::normalize_code("7kq4-m9xd 2hpr-oil")
+}
+`,
+				Check: resource.TestCheckOutput("test", "7KQ4M9XD2HPR011"),
+			},
+		},
+	})
+}
+
+func TestAccNormalizeCodeFunction_WithOptions(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		TerraformVersionChecks: []tfversion.TerraformVersionCheck{
+			tfversion.SkipBelow(tfversion.Version1_8_0),
+		},
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+resource "nanoid_code" "test" {
+  alphabet  = "abcdefgh-"
+  separator = "."
+}
+
+output "test" {
+  value = provider::nanoid::normalize_code(upper(nanoid_code.test.id), nanoid_code.test.alphabet, nanoid_code.test.separator) == nanoid_code.test.normalized
+}
+
+output "lowercase" {
+  value = provider::nanoid::normalize_code("AB-C. d", "abcd-", ".")
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckOutput("test", "true"),
+					resource.TestCheckOutput("lowercase", "ab-cd"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccNormalizeCodeFunction_SeparatorInAlphabet(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		TerraformVersionChecks: []tfversion.TerraformVersionCheck{
+			tfversion.SkipBelow(tfversion.Version1_8_0),
+		},
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+output "test" {
+  value = provider::nanoid::normalize_code("AB-CD", "ABCD-", "-")
+}
+`,
+				ExpectError: regexp.MustCompile(`must\s+not\s+contain\s+characters\s+of\s+the\s+alphabet`),
+			},
+		},
+	})
+}
+
+func TestAccNormalizeCodeFunction_WithResource(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		TerraformVersionChecks: []tfversion.TerraformVersionCheck{
+			tfversion.SkipBelow(tfversion.Version1_8_0),
+		},
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+resource "nanoid_code" "test" {}
+
+output "test" {
+  value = provider::nanoid::normalize_code(lower(nanoid_code.test.id)) == nanoid_code.test.normalized
+}
+`,
+				Check: resource.TestCheckOutput("test", "true"),
+			},
+		},
+	})
+}
+
+func TestAccNormalizeCodeFunction_Invalid(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		TerraformVersionChecks: []tfversion.TerraformVersionCheck{
+			tfversion.SkipBelow(tfversion.Version1_8_0),
+		},
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+output "test" {
+  value = provider::nanoid::normalize_code("7KQ4-U9XD")
+}
+`,
+				ExpectError: regexp.MustCompile(`is not a valid code character`),
+			},
+		},
+	})
+}
@@ -0,0 +1,81 @@
+// Copyright (c) The Nanoid Provider for Terraform Authors
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"math"
+
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ function.Function = &SqidsDecodeFunction{}
+
+func NewSqidsDecodeFunction() function.Function {
+	return &SqidsDecodeFunction{}
+}
+
+// SqidsDecodeFunction defines the function implementation.
+type SqidsDecodeFunction struct{}
+
+func (f *SqidsDecodeFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "sqids_decode"
+}
+
+func (f *SqidsDecodeFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary: "Decode the integers of a sqid",
+		MarkdownDescription: "Returns the list of integers encoded in a [Sqids](https://sqids.org) id, " +
+			"such as the `sqid` of a `nanoid_sqid` resource decoded with its `shuffled_alphabet`.\n\n" +
+			"An id that was not generated with the alphabet may decode to unrelated integers, " +
+			"so compare the result of `sqids_encode` on the decoded integers with the id when it is untrusted.",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:                "sqid",
+				MarkdownDescription: "The id to decode.",
+			},
+			function.StringParameter{
+				Name:                "alphabet",
+				MarkdownDescription: "The alphabet the id was encoded with.",
+			},
+		},
+		Return: function.ListReturn{
+			ElementType: types.Int64Type,
+		},
+	}
+}
+
+func (f *SqidsDecodeFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var sqid, alphabet string
+	resp.Error = function.ConcatFuncErrors(resp.Error, req.Arguments.Get(ctx, &sqid, &alphabet))
+	if resp.Error != nil {
+		return
+	}
+
+	s, err := newSqids(alphabet, DEFAULT_SQIDS_MIN_LENGTH)
+	if err != nil {
+		resp.Error = function.ConcatFuncErrors(resp.Error, function.NewArgumentFuncError(1, fmt.Sprintf("Failed to decode sqid: %s.", err)))
+		return
+	}
+
+	decoded, err := s.decode(sqid)
+	if err != nil {
+		resp.Error = function.ConcatFuncErrors(resp.Error, function.NewArgumentFuncError(0, fmt.Sprintf("Failed to decode sqid: %s.", err)))
+		return
+	}
+
+	numbers := make([]int64, len(decoded))
+	for i, n := range decoded {
+		if n > math.MaxInt64 {
+			resp.Error = function.ConcatFuncErrors(resp.Error, function.NewArgumentFuncError(0, fmt.Sprintf("Failed to decode sqid: the number %d at index %d is too large.", n, i)))
+			return
+		}
+		numbers[i] = int64(n)
+	}
+
+	resp.Error = function.ConcatFuncErrors(resp.Error, resp.Result.Set(ctx, numbers))
+}
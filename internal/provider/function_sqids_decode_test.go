@@ -0,0 +1,73 @@
+// Copyright (c) The Nanoid Provider for Terraform Authors
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/tfversion"
+)
+
+func TestAccSqidsDecodeFunction(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		TerraformVersionChecks: []tfversion.TerraformVersionCheck{
+			tfversion.SkipBelow(tfversion.Version1_8_0),
+		},
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+output "test" {
+  value = jsonencode(provider::nanoid::sqids_decode("86Rf07xd4z", "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"))
+}
+`,
+				Check: resource.TestCheckOutput("test", "[1,2,3]"),
+			},
+		},
+	})
+}
+
+func TestAccSqidsDecodeFunction_WithResource(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		TerraformVersionChecks: []tfversion.TerraformVersionCheck{
+			tfversion.SkipBelow(tfversion.Version1_8_0),
+		},
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+resource "nanoid_sqid" "test" {
+  numbers = [123456789, 0]
+}
+
+output "test" {
+  value = jsonencode(provider::nanoid::sqids_decode(nanoid_sqid.test.sqid, nanoid_sqid.test.shuffled_alphabet))
+}
+`,
+				Check: resource.TestCheckOutput("test", "[123456789,0]"),
+			},
+		},
+	})
+}
+
+func TestAccSqidsDecodeFunction_Invalid(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		TerraformVersionChecks: []tfversion.TerraformVersionCheck{
+			tfversion.SkipBelow(tfversion.Version1_8_0),
+		},
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+output "test" {
+  value = provider::nanoid::sqids_decode("86Rf-07", "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789")
+}
+`,
+				ExpectError: regexp.MustCompile(`is\s+not\s+part\s+of\s+the\s+alphabet`),
+			},
+		},
+	})
+}
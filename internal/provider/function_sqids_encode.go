@@ -0,0 +1,85 @@
+// Copyright (c) The Nanoid Provider for Terraform Authors
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ function.Function = &SqidsEncodeFunction{}
+
+func NewSqidsEncodeFunction() function.Function {
+	return &SqidsEncodeFunction{}
+}
+
+// SqidsEncodeFunction defines the function implementation.
+type SqidsEncodeFunction struct{}
+
+func (f *SqidsEncodeFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "sqids_encode"
+}
+
+func (f *SqidsEncodeFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary: "Encode integers into a sqid",
+		MarkdownDescription: "Returns the [Sqids](https://sqids.org) id of a list of non-negative integers, " +
+			"as generated by the `nanoid_sqid` resource from its `shuffled_alphabet`.",
+		Parameters: []function.Parameter{
+			function.ListParameter{
+				Name:                "numbers",
+				ElementType:         types.Int64Type,
+				MarkdownDescription: "The non-negative integers to encode.",
+			},
+			function.StringParameter{
+				Name:                "alphabet",
+				MarkdownDescription: "The alphabet to encode with, such as the `shuffled_alphabet` of a `nanoid_sqid` resource.",
+			},
+			function.Int64Parameter{
+				Name:                "min_length",
+				MarkdownDescription: fmt.Sprintf("The minimum length of the sqid, between 0 and %d.", MAX_SQIDS_MIN_LENGTH),
+			},
+		},
+		Return: function.StringReturn{},
+	}
+}
+
+func (f *SqidsEncodeFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var numbers []int64
+	var alphabet string
+	var minLength int64
+	resp.Error = function.ConcatFuncErrors(resp.Error, req.Arguments.Get(ctx, &numbers, &alphabet, &minLength))
+	if resp.Error != nil {
+		return
+	}
+
+	for i, n := range numbers {
+		if n < 0 {
+			resp.Error = function.ConcatFuncErrors(resp.Error, function.NewArgumentFuncError(0, fmt.Sprintf("Failed to encode sqid: the number %d at index %d is negative.", n, i)))
+			return
+		}
+	}
+	if minLength < 0 || minLength > MAX_SQIDS_MIN_LENGTH {
+		resp.Error = function.ConcatFuncErrors(resp.Error, function.NewArgumentFuncError(2, fmt.Sprintf("Failed to encode sqid: the minimum length must be between 0 and %d.", MAX_SQIDS_MIN_LENGTH)))
+		return
+	}
+
+	s, err := newSqids(alphabet, int(minLength))
+	if err != nil {
+		resp.Error = function.ConcatFuncErrors(resp.Error, function.NewArgumentFuncError(1, fmt.Sprintf("Failed to encode sqid: %s.", err)))
+		return
+	}
+
+	sqid, err := s.encode(sqidsNumbers(numbers))
+	if err != nil {
+		resp.Error = function.ConcatFuncErrors(resp.Error, function.NewFuncError(fmt.Sprintf("Failed to encode sqid: %s.", err)))
+		return
+	}
+
+	resp.Error = function.ConcatFuncErrors(resp.Error, resp.Result.Set(ctx, sqid))
+}
@@ -0,0 +1,74 @@
+// Copyright (c) The Nanoid Provider for Terraform Authors
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/tfversion"
+)
+
+func TestAccSqidsEncodeFunction(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		TerraformVersionChecks: []tfversion.TerraformVersionCheck{
+			tfversion.SkipBelow(tfversion.Version1_8_0),
+		},
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+output "test" {
+  value = provider::nanoid::sqids_encode([1, 2, 3], "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789", 10)
+}
+`,
+				Check: resource.TestCheckOutput("test", "86Rf07xd4z"),
+			},
+		},
+	})
+}
+
+func TestAccSqidsEncodeFunction_WithResource(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		TerraformVersionChecks: []tfversion.TerraformVersionCheck{
+			tfversion.SkipBelow(tfversion.Version1_8_0),
+		},
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+resource "nanoid_sqid" "test" {
+  numbers    = [10, 20]
+  min_length = 8
+}
+
+output "test" {
+  value = provider::nanoid::sqids_encode([10, 20], nanoid_sqid.test.shuffled_alphabet, 8) == nanoid_sqid.test.sqid
+}
+`,
+				Check: resource.TestCheckOutput("test", "true"),
+			},
+		},
+	})
+}
+
+func TestAccSqidsEncodeFunction_Negative(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		TerraformVersionChecks: []tfversion.TerraformVersionCheck{
+			tfversion.SkipBelow(tfversion.Version1_8_0),
+		},
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+output "test" {
+  value = provider::nanoid::sqids_encode([1, -2], "abc", 0)
+}
+`,
+				ExpectError: regexp.MustCompile(`the\s+number\s+-2\s+at\s+index\s+1\s+is\s+negative`),
+			},
+		},
+	})
+}
@@ -0,0 +1,64 @@
+// Copyright (c) The Nanoid Provider for Terraform Authors
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/function"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ function.Function = &VerifyChecksumFunction{}
+
+func NewVerifyChecksumFunction() function.Function {
+	return &VerifyChecksumFunction{}
+}
+
+// VerifyChecksumFunction defines the function implementation.
+type VerifyChecksumFunction struct{}
+
+func (f *VerifyChecksumFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "verify_checksum"
+}
+
+func (f *VerifyChecksumFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary: "Verify the check character of an id",
+		MarkdownDescription: "Returns `true` when the last character of the id is the check character of the characters preceding it, " +
+			"as generated by the `checksum` attribute of the `nanoid_id` resource.",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:                "id",
+				MarkdownDescription: "The id to verify, including its check character.",
+			},
+			function.StringParameter{
+				Name:                "alphabet",
+				MarkdownDescription: "The alphabet the id was generated with.",
+			},
+			function.StringParameter{
+				Name:                "algorithm",
+				MarkdownDescription: "The checksum algorithm, one of `luhn`, `damm`, `verhoeff` or `iso7064`.",
+			},
+		},
+		Return: function.BoolReturn{},
+	}
+}
+
+func (f *VerifyChecksumFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var id, alphabet, algorithm string
+	resp.Error = function.ConcatFuncErrors(resp.Error, req.Arguments.Get(ctx, &id, &alphabet, &algorithm))
+	if resp.Error != nil {
+		return
+	}
+
+	valid, err := verifyChecksum(algorithm, alphabet, id)
+	if err != nil {
+		resp.Error = function.ConcatFuncErrors(resp.Error, function.NewFuncError(fmt.Sprintf("Failed to verify checksum: %s.", err)))
+		return
+	}
+
+	resp.Error = function.ConcatFuncErrors(resp.Error, resp.Result.Set(ctx, valid))
+}
@@ -0,0 +1,80 @@
+// Copyright (c) The Nanoid Provider for Terraform Authors
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/tfversion"
+)
+
+func TestAccVerifyChecksumFunction(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		TerraformVersionChecks: []tfversion.TerraformVersionCheck{
+			tfversion.SkipBelow(tfversion.Version1_8_0),
+		},
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+output "valid" {
+  value = provider::nanoid::verify_checksum("5724", "0123456789", "damm")
+}
+
+output "invalid" {
+  value = provider::nanoid::verify_checksum("5274", "0123456789", "damm")
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckOutput("valid", "true"),
+					resource.TestCheckOutput("invalid", "false"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccVerifyChecksumFunction_WithResource(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		TerraformVersionChecks: []tfversion.TerraformVersionCheck{
+			tfversion.SkipBelow(tfversion.Version1_8_0),
+		},
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+resource "nanoid_id" "test" {
+  checksum = "iso7064"
+}
+
+output "valid" {
+  value = provider::nanoid::verify_checksum(nanoid_id.test.id, nanoid_id.test.alphabet, nanoid_id.test.checksum)
+}
+`,
+				Check: resource.TestCheckOutput("valid", "true"),
+			},
+		},
+	})
+}
+
+func TestAccVerifyChecksumFunction_UnsupportedAlphabet(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		TerraformVersionChecks: []tfversion.TerraformVersionCheck{
+			tfversion.SkipBelow(tfversion.Version1_8_0),
+		},
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+output "test" {
+  value = provider::nanoid::verify_checksum("abcd", "abcdef", "verhoeff")
+}
+`,
+				ExpectError: regexp.MustCompile(`requires an alphabet of exactly 10\s+characters`),
+			},
+		},
+	})
+}
@@ -0,0 +1,126 @@
+// Copyright (c) The Nanoid Provider for Terraform Authors
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// ID_ALGORITHM names the algorithm generating the ids of nanoid_id and
+// nanoid_dns resources, recorded so that ids can be migrated if it changes.
+const ID_ALGORITHM = "nanoid-v1-crypto"
+
+// planKeepersRegeneration plans the generation of the id of a resource, and
+// returns whether the id of an existing resource is regenerated in place
+// because its configured keepers or sensitive keepers changed.
+//
+// A configured generation other than the one of the id regenerates it like
+// changed keepers, and is planned instead of the next generation.
+//
+// Changed keepers require a replacement, unless regenerate_in_place is set or
+// the resource keeps a history of its ids: the id is then regenerated in
+// place, incrementing its generation, as Terraform plans a replacing resource
+// without the state of the replaced one.
+func planKeepersRegeneration(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse, keepsHistory bool) bool {
+	if req.Plan.Raw.IsNull() {
+		return false
+	}
+
+	var configGeneration types.Int64
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("generation"), &configGeneration)...)
+	if resp.Diagnostics.HasError() {
+		return false
+	}
+
+	if req.State.Raw.IsNull() {
+		if configGeneration.IsNull() {
+			resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("generation"), types.Int64Value(1))...)
+		}
+		return false
+	}
+
+	var generation types.Int64
+	var inPlace types.Bool
+	var sensitiveDigest, seed types.String
+	changes, diags := keepersChanges(ctx, req)
+	resp.Diagnostics.Append(diags...)
+	resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root("generation"), &generation)...)
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("regenerate_in_place"), &inPlace)...)
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("keepers_sensitive_digest"), &sensitiveDigest)...)
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("seed"), &seed)...)
+	if resp.Diagnostics.HasError() {
+		return false
+	}
+
+	if !configGeneration.IsNull() && !configGeneration.Equal(generation) {
+		changes = append(changes, path.Root("generation"))
+	}
+
+	// The digest of the sensitive keepers is only planned unknown when they change.
+	if len(changes) == 0 && !sensitiveDigest.IsUnknown() {
+		return false
+	}
+
+	if !inPlace.ValueBool() && !keepsHistory {
+		resp.RequiresReplace = append(resp.RequiresReplace, changes...)
+		if sensitiveDigest.IsUnknown() {
+			resp.RequiresReplace = append(resp.RequiresReplace, path.Root("keepers_sensitive_digest"))
+		}
+		return false
+	}
+
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("id"), types.StringUnknown())...)
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("created_at"), types.StringUnknown())...)
+	algorithm := types.StringValue(ID_ALGORITHM)
+	switch {
+	case seed.IsUnknown():
+		algorithm = types.StringUnknown()
+	case !seed.IsNull():
+		algorithm = types.StringValue(SEEDED_ID_ALGORITHM)
+	}
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("algorithm"), algorithm)...)
+	if configGeneration.IsNull() {
+		resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("generation"), types.Int64Value(generation.ValueInt64()+1))...)
+	}
+
+	return !resp.Diagnostics.HasError()
+}
+
+// planPreviousIds plans the previous ids of a resource, adding its current id
+// when it is regenerated and keeping at most historySize of them.
+func planPreviousIds(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse, historySize types.Int64, regenerate bool) (diags diag.Diagnostics) {
+	if historySize.IsUnknown() {
+		diags.Append(resp.Plan.SetAttribute(ctx, path.Root("previous_ids"), types.ListUnknown(types.StringType))...)
+		return diags
+	}
+
+	previous := []string{}
+	if !req.State.Raw.IsNull() {
+		var id types.String
+		var stateIds types.List
+		diags.Append(req.State.GetAttribute(ctx, path.Root("id"), &id)...)
+		diags.Append(req.State.GetAttribute(ctx, path.Root("previous_ids"), &stateIds)...)
+		if diags.HasError() {
+			return diags
+		}
+		if !stateIds.IsNull() {
+			diags.Append(stateIds.ElementsAs(ctx, &previous, false)...)
+		}
+		if regenerate {
+			previous = append([]string{id.ValueString()}, previous...)
+		}
+	}
+
+	if size := int(historySize.ValueInt64()); len(previous) > size {
+		previous = previous[:size]
+	}
+	diags.Append(resp.Plan.SetAttribute(ctx, path.Root("previous_ids"), previous)...)
+
+	return diags
+}
@@ -0,0 +1,420 @@
+// Copyright (c) The Nanoid Provider for Terraform Authors
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+// KEEPERS_SCHEMA_VERSION is the version of the nanoid_id and nanoid_dns
+// schemas in which keepers is a dynamic value rather than a map of strings.
+const KEEPERS_SCHEMA_VERSION = 1
+
+// keepersChanges returns the paths of the nested keepers values changed by a
+// plan, or none if the keepers are not configured.
+func keepersChanges(ctx context.Context, req resource.ModifyPlanRequest) (path.Paths, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	var configKeepers, planKeepers, stateKeepers types.Dynamic
+	diags.Append(req.Config.GetAttribute(ctx, path.Root("keepers"), &configKeepers)...)
+	diags.Append(req.Plan.GetAttribute(ctx, path.Root("keepers"), &planKeepers)...)
+	diags.Append(req.State.GetAttribute(ctx, path.Root("keepers"), &stateKeepers)...)
+	if diags.HasError() {
+		return nil, diags
+	}
+
+	// Like a replacement if configured, removing the keepers keeps the resource.
+	if configKeepers.IsNull() || configKeepers.IsUnderlyingValueNull() {
+		return nil, diags
+	}
+
+	planValue, err := planKeepers.ToTerraformValue(ctx)
+	if err != nil {
+		diags.AddError("Invalid keepers", fmt.Sprintf("Invalid planned keepers: %s.", err))
+		return nil, diags
+	}
+	stateValue, err := stateKeepers.ToTerraformValue(ctx)
+	if err != nil {
+		diags.AddError("Invalid keepers", fmt.Sprintf("Invalid keepers in state: %s.", err))
+		return nil, diags
+	}
+
+	return keepersDiff(path.Root("keepers"), stateValue, planValue), diags
+}
+
+// keepersDiff returns the paths at which two keepers values differ. Maps are
+// compared like objects, lists like tuples, and primitive values by
+// their string form, so that only changes of values, not of their types,
+// are reported.
+func keepersDiff(p path.Path, state, plan tftypes.Value) path.Paths {
+	if !plan.IsKnown() || !state.IsKnown() {
+		return path.Paths{p}
+	}
+	if plan.IsNull() || state.IsNull() {
+		if plan.IsNull() && state.IsNull() {
+			return nil
+		}
+		return path.Paths{p}
+	}
+
+	switch {
+	case isKeyedType(state.Type()) && isKeyedType(plan.Type()):
+		var stateValues, planValues map[string]tftypes.Value
+		if state.As(&stateValues) != nil || plan.As(&planValues) != nil {
+			return path.Paths{p}
+		}
+
+		keys := make([]string, 0, len(stateValues)+len(planValues))
+		for k := range stateValues {
+			keys = append(keys, k)
+		}
+		for k := range planValues {
+			if _, ok := stateValues[k]; !ok {
+				keys = append(keys, k)
+			}
+		}
+		sort.Strings(keys)
+
+		var paths path.Paths
+		for _, k := range keys {
+			step := p.AtMapKey(k)
+			if plan.Type().Is(tftypes.Object{}) {
+				step = p.AtName(k)
+			}
+			stateValue, inState := stateValues[k]
+			planValue, inPlan := planValues[k]
+			if !inState || !inPlan {
+				paths = append(paths, step)
+				continue
+			}
+			paths = append(paths, keepersDiff(step, stateValue, planValue)...)
+		}
+		return paths
+
+	case isIndexedType(state.Type()) && isIndexedType(plan.Type()):
+		var stateValues, planValues []tftypes.Value
+		if state.As(&stateValues) != nil || plan.As(&planValues) != nil {
+			return path.Paths{p}
+		}
+		// Elements of sets have no index to report.
+		if state.Type().Is(tftypes.Set{}) || plan.Type().Is(tftypes.Set{}) {
+			if indexedEqual(stateValues, planValues) {
+				return nil
+			}
+			return path.Paths{p}
+		}
+
+		var paths path.Paths
+		for i := 0; i < len(stateValues) || i < len(planValues); i++ {
+			step := p.AtListIndex(i)
+			if i >= len(stateValues) || i >= len(planValues) {
+				paths = append(paths, step)
+				continue
+			}
+			paths = append(paths, keepersDiff(step, stateValues[i], planValues[i])...)
+		}
+		return paths
+
+	default:
+		stateString, stateOk := primitiveString(state)
+		planString, planOk := primitiveString(plan)
+		if stateOk && planOk && stateString == planString {
+			return nil
+		}
+		if !stateOk && !planOk && state.Equal(plan) {
+			return nil
+		}
+		return path.Paths{p}
+	}
+}
+
+// indexedEqual returns whether two sequences of keepers values are equal.
+func indexedEqual(stateValues, planValues []tftypes.Value) bool {
+	if len(stateValues) != len(planValues) {
+		return false
+	}
+	for i := range stateValues {
+		if len(keepersDiff(path.Empty(), stateValues[i], planValues[i])) > 0 {
+			return false
+		}
+	}
+	return true
+}
+
+func isKeyedType(t tftypes.Type) bool {
+	return t.Is(tftypes.Map{}) || t.Is(tftypes.Object{})
+}
+
+func isIndexedType(t tftypes.Type) bool {
+	return t.Is(tftypes.List{}) || t.Is(tftypes.Tuple{}) || t.Is(tftypes.Set{})
+}
+
+// primitiveString returns the string form of a known primitive value, as
+// Terraform converts it to a string.
+func primitiveString(v tftypes.Value) (string, bool) {
+	switch {
+	case v.Type().Is(tftypes.String):
+		var s string
+		return s, v.As(&s) == nil
+	case v.Type().Is(tftypes.Number):
+		n := new(big.Float)
+		if v.As(&n) != nil {
+			return "", false
+		}
+		return n.Text('f', -1), true
+	case v.Type().Is(tftypes.Bool):
+		var b bool
+		if v.As(&b) != nil {
+			return "", false
+		}
+		return fmt.Sprint(b), true
+	}
+	return "", false
+}
+
+// keepersStateUpgrader upgrades the state of a resource from the version 0 of
+// its schema, in which keepers was a map of strings. The keepers become an
+// object of strings, which is how Terraform types the keepers configured as
+// a map literal, so that upgraded resources plan no changes.
+func keepersStateUpgrader() resource.StateUpgrader {
+	return resource.StateUpgrader{
+		StateUpgrader: func(ctx context.Context, req resource.UpgradeStateRequest, resp *resource.UpgradeStateResponse) {
+			upgraded, err := upgradeKeepersState(req.RawState.JSON)
+			if err != nil {
+				resp.Diagnostics.AddError("Unable to upgrade state", fmt.Sprintf("Unable to upgrade the keepers in state: %s.", err))
+				return
+			}
+			resp.DynamicValue = &tfprotov6.DynamicValue{JSON: upgraded}
+		},
+	}
+}
+
+// upgradeKeepersState returns the JSON state of a resource with its keepers
+// map of strings encoded as a dynamic object value.
+func upgradeKeepersState(state []byte) ([]byte, error) {
+	var attributes map[string]json.RawMessage
+	if err := json.Unmarshal(state, &attributes); err != nil {
+		return nil, err
+	}
+
+	var keepers map[string]*string
+	if raw, ok := attributes["keepers"]; ok {
+		if err := json.Unmarshal(raw, &keepers); err != nil {
+			return nil, err
+		}
+	}
+	if keepers != nil {
+		attributeTypes := make(map[string]string, len(keepers))
+		for k := range keepers {
+			attributeTypes[k] = "string"
+		}
+		value, err := json.Marshal(map[string]any{
+			"type":  []any{"object", attributeTypes},
+			"value": keepers,
+		})
+		if err != nil {
+			return nil, err
+		}
+		attributes["keepers"] = value
+	}
+
+	return json.Marshal(attributes)
+}
+
+// KEEPERS_SENSITIVE_SALT_LENGTH is the length in bytes of the salt of the
+// digest of sensitive keepers.
+const KEEPERS_SENSITIVE_SALT_LENGTH = 16
+
+// keepersSensitiveDigest returns the digest of sensitive keepers, of the form
+// sha256:<salt>:<digest> with the salt and the digest hex encoded. Like the
+// keepers, the values are canonicalized so that only changes of values, not
+// of their types, change the digest.
+func keepersSensitiveDigest(value tftypes.Value, salt []byte) (string, error) {
+	canonical, err := canonicalKeepers(value)
+	if err != nil {
+		return "", err
+	}
+	encoded, err := json.Marshal(canonical)
+	if err != nil {
+		return "", err
+	}
+
+	hash := sha256.New()
+	hash.Write(salt)
+	hash.Write(encoded)
+	return fmt.Sprintf("sha256:%x:%x", salt, hash.Sum(nil)), nil
+}
+
+// matchesKeepersSensitiveDigest returns whether sensitive keepers have the
+// digest recorded in state.
+func matchesKeepersSensitiveDigest(digest string, value tftypes.Value) bool {
+	parts := strings.Split(digest, ":")
+	if len(parts) != 3 || parts[0] != "sha256" {
+		return false
+	}
+	salt, err := hex.DecodeString(parts[1])
+	if err != nil {
+		return false
+	}
+	expected, err := keepersSensitiveDigest(value, salt)
+	if err != nil {
+		return false
+	}
+	return subtle.ConstantTimeCompare([]byte(expected), []byte(digest)) == 1
+}
+
+// canonicalKeepers returns keepers values as maps, slices and strings, which
+// JSON encodes with sorted keys.
+func canonicalKeepers(value tftypes.Value) (any, error) {
+	if !value.IsFullyKnown() {
+		return nil, fmt.Errorf("unknown value")
+	}
+	if value.IsNull() {
+		return nil, nil
+	}
+
+	switch {
+	case isKeyedType(value.Type()):
+		var values map[string]tftypes.Value
+		if err := value.As(&values); err != nil {
+			return nil, err
+		}
+		canonical := make(map[string]any, len(values))
+		for k, v := range values {
+			c, err := canonicalKeepers(v)
+			if err != nil {
+				return nil, err
+			}
+			canonical[k] = c
+		}
+		return canonical, nil
+
+	case isIndexedType(value.Type()):
+		var values []tftypes.Value
+		if err := value.As(&values); err != nil {
+			return nil, err
+		}
+		canonical := make([]any, len(values))
+		for i, v := range values {
+			c, err := canonicalKeepers(v)
+			if err != nil {
+				return nil, err
+			}
+			canonical[i] = c
+		}
+		return canonical, nil
+	}
+
+	s, ok := primitiveString(value)
+	if !ok {
+		return nil, fmt.Errorf("unsupported type %s", value.Type())
+	}
+	return s, nil
+}
+
+// setKeepersSensitiveDigest records the digest of the configured sensitive
+// keepers, with a new salt, unless digest already holds their digest.
+func setKeepersSensitiveDigest(ctx context.Context, config tfsdk.Config, digest *types.String) (diags diag.Diagnostics) {
+	var keepers types.Dynamic
+	diags.Append(config.GetAttribute(ctx, path.Root("keepers_sensitive"), &keepers)...)
+	if diags.HasError() {
+		return diags
+	}
+
+	if keepers.IsNull() || keepers.IsUnderlyingValueNull() {
+		*digest = types.StringNull()
+		return diags
+	}
+	if !digest.IsNull() && !digest.IsUnknown() {
+		return diags
+	}
+
+	value, err := keepers.ToTerraformValue(ctx)
+	if err != nil {
+		diags.AddError("Invalid keepers_sensitive", fmt.Sprintf("Invalid keepers_sensitive: %s.", err))
+		return diags
+	}
+	salt := make([]byte, KEEPERS_SENSITIVE_SALT_LENGTH)
+	if _, err := rand.Read(salt); err != nil {
+		diags.AddError("Unable to generate salt", fmt.Sprintf("Unable to generate the salt of keepers_sensitive_digest: %s.", err))
+		return diags
+	}
+	d, err := keepersSensitiveDigest(value, salt)
+	if err != nil {
+		diags.AddError("Invalid keepers_sensitive", fmt.Sprintf("Invalid keepers_sensitive: %s.", err))
+		return diags
+	}
+	*digest = types.StringValue(d)
+
+	return diags
+}
+
+var _ planmodifier.String = keepersSensitiveDigestModifier{}
+
+// keepersSensitiveDigestModifier plans the digest of the sensitive keepers
+// of a resource, comparing their configured value with the digest in state.
+// The digest is planned unknown when they change, replacing the resource
+// if requiresReplace is set.
+type keepersSensitiveDigestModifier struct {
+	requiresReplace bool
+}
+
+func (m keepersSensitiveDigestModifier) Description(ctx context.Context) string {
+	return "value is the digest of keepers_sensitive in state, or unknown when they change"
+}
+
+func (m keepersSensitiveDigestModifier) MarkdownDescription(ctx context.Context) string {
+	return "value is the digest of `keepers_sensitive` in state, or unknown when they change"
+}
+
+func (m keepersSensitiveDigestModifier) PlanModifyString(ctx context.Context, req planmodifier.StringRequest, resp *planmodifier.StringResponse) {
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+
+	var keepers types.Dynamic
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("keepers_sensitive"), &keepers)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Like the keepers, removing the sensitive keepers keeps the resource.
+	if keepers.IsNull() || keepers.IsUnderlyingValueNull() {
+		resp.PlanValue = types.StringNull()
+		return
+	}
+
+	resp.PlanValue = types.StringUnknown()
+	if req.State.Raw.IsNull() {
+		return
+	}
+
+	value, err := keepers.ToTerraformValue(ctx)
+	if err != nil {
+		resp.Diagnostics.AddError("Invalid keepers_sensitive", fmt.Sprintf("Invalid keepers_sensitive: %s.", err))
+		return
+	}
+	if value.IsFullyKnown() && !req.StateValue.IsNull() && matchesKeepersSensitiveDigest(req.StateValue.ValueString(), value) {
+		resp.PlanValue = req.StateValue
+		return
+	}
+	resp.RequiresReplace = m.requiresReplace
+}
@@ -0,0 +1,202 @@
+// Copyright (c) The Nanoid Provider for Terraform Authors
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+func TestKeepersDiff(t *testing.T) {
+	str := func(s string) tftypes.Value { return tftypes.NewValue(tftypes.String, s) }
+	num := func(n int64) tftypes.Value { return tftypes.NewValue(tftypes.Number, n) }
+	object := func(values map[string]tftypes.Value) tftypes.Value {
+		types := make(map[string]tftypes.Type, len(values))
+		for k, v := range values {
+			types[k] = v.Type()
+		}
+		return tftypes.NewValue(tftypes.Object{AttributeTypes: types}, values)
+	}
+	tuple := func(values ...tftypes.Value) tftypes.Value {
+		types := make([]tftypes.Type, len(values))
+		for i, v := range values {
+			types[i] = v.Type()
+		}
+		return tftypes.NewValue(tftypes.Tuple{ElementTypes: types}, values)
+	}
+	stringMap := func(values map[string]tftypes.Value) tftypes.Value {
+		return tftypes.NewValue(tftypes.Map{ElementType: tftypes.String}, values)
+	}
+	root := path.Root("keepers")
+
+	cases := map[string]struct {
+		state, plan tftypes.Value
+		expected    path.Paths
+	}{
+		"equal": {
+			state: object(map[string]tftypes.Value{"a": str("x")}),
+			plan:  object(map[string]tftypes.Value{"a": str("x")}),
+		},
+		"map to object": {
+			state: stringMap(map[string]tftypes.Value{"a": str("x")}),
+			plan:  object(map[string]tftypes.Value{"a": str("x")}),
+		},
+		"string to number": {
+			state: object(map[string]tftypes.Value{"n": str("1")}),
+			plan:  object(map[string]tftypes.Value{"n": num(1)}),
+		},
+		"nested attribute": {
+			state: object(map[string]tftypes.Value{"a": object(map[string]tftypes.Value{"b": str("x"), "c": str("y")})}),
+			plan:  object(map[string]tftypes.Value{"a": object(map[string]tftypes.Value{"b": str("z"), "c": str("y")})}),
+			expected: path.Paths{
+				root.AtName("a").AtName("b"),
+			},
+		},
+		"map key": {
+			state: stringMap(map[string]tftypes.Value{"a": str("x"), "b": str("y")}),
+			plan:  stringMap(map[string]tftypes.Value{"a": str("x"), "b": str("z")}),
+			expected: path.Paths{
+				root.AtMapKey("b"),
+			},
+		},
+		"added and removed attributes": {
+			state: object(map[string]tftypes.Value{"a": str("x"), "b": str("y")}),
+			plan:  object(map[string]tftypes.Value{"a": str("x"), "c": str("y")}),
+			expected: path.Paths{
+				root.AtName("b"),
+				root.AtName("c"),
+			},
+		},
+		"tuple element": {
+			state: tuple(str("x"), num(2)),
+			plan:  tuple(str("x"), num(3), str("y")),
+			expected: path.Paths{
+				root.AtListIndex(1),
+				root.AtListIndex(2),
+			},
+		},
+		"changed type": {
+			state: object(map[string]tftypes.Value{"a": str("x")}),
+			plan:  object(map[string]tftypes.Value{"a": tuple(str("x"))}),
+			expected: path.Paths{
+				root.AtName("a"),
+			},
+		},
+		"unknown": {
+			state: object(map[string]tftypes.Value{"a": str("x")}),
+			plan:  object(map[string]tftypes.Value{"a": tftypes.NewValue(tftypes.String, tftypes.UnknownValue)}),
+			expected: path.Paths{
+				root.AtName("a"),
+			},
+		},
+		"null": {
+			state: tftypes.NewValue(tftypes.DynamicPseudoType, nil),
+			plan:  object(map[string]tftypes.Value{"a": str("x")}),
+			expected: path.Paths{
+				root,
+			},
+		},
+	}
+
+	for name, c := range cases {
+		diff := keepersDiff(root, c.state, c.plan)
+		if len(diff) != len(c.expected) {
+			t.Errorf("%s: expected %v, got %v", name, c.expected, diff)
+			continue
+		}
+		for i := range diff {
+			if !diff[i].Equal(c.expected[i]) {
+				t.Errorf("%s: expected %v, got %v", name, c.expected, diff)
+				break
+			}
+		}
+	}
+}
+
+func TestUpgradeKeepersState(t *testing.T) {
+	var r IdResource
+	var schemaResp resource.SchemaResponse
+	r.Schema(context.Background(), resource.SchemaRequest{}, &schemaResp)
+	schemaType := schemaResp.Schema.Type().TerraformType(context.Background())
+
+	cases := map[string]string{
+		"keepers": `{"id":"V1StGXR8_Z5jdHi6B-myT","keepers":{"a":"x","b":null}}`,
+		"null":    `{"id":"V1StGXR8_Z5jdHi6B-myT","keepers":null}`,
+	}
+
+	for name, state := range cases {
+		upgraded, err := upgradeKeepersState([]byte(state))
+		if err != nil {
+			t.Errorf("%s: unexpected error: %s", name, err)
+			continue
+		}
+
+		value, err := tftypes.ValueFromJSONWithOpts(upgraded, schemaType, tftypes.ValueFromJSONOpts{IgnoreUndefinedAttributes: true})
+		if err != nil {
+			t.Errorf("%s: unable to decode upgraded state %s: %s", name, upgraded, err)
+			continue
+		}
+
+		keepers, _, err := tftypes.WalkAttributePath(value, tftypes.NewAttributePath().WithAttributeName("keepers"))
+		if err != nil {
+			t.Errorf("%s: %s", name, err)
+			continue
+		}
+		if name == "null" {
+			if !keepers.(tftypes.Value).IsNull() {
+				t.Errorf("%s: expected null keepers, got %s", name, keepers)
+			}
+			continue
+		}
+
+		expected := tftypes.NewValue(tftypes.Object{AttributeTypes: map[string]tftypes.Type{"a": tftypes.String, "b": tftypes.String}}, map[string]tftypes.Value{
+			"a": tftypes.NewValue(tftypes.String, "x"),
+			"b": tftypes.NewValue(tftypes.String, nil),
+		})
+		if !keepers.(tftypes.Value).Equal(expected) {
+			t.Errorf("%s: expected %s, got %s", name, expected, keepers)
+		}
+	}
+}
+
+func TestKeepersSensitiveDigest(t *testing.T) {
+	version := func(v tftypes.Value) tftypes.Value {
+		return tftypes.NewValue(tftypes.Object{AttributeTypes: map[string]tftypes.Type{"version": v.Type()}}, map[string]tftypes.Value{"version": v})
+	}
+	salt := []byte("0123456789abcdef")
+
+	digest, err := keepersSensitiveDigest(version(tftypes.NewValue(tftypes.String, "3")), salt)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if !regexp.MustCompile(`^sha256:[0-9a-f]{32}:[0-9a-f]{64}$`).MatchString(digest) {
+		t.Errorf("unexpected digest %q", digest)
+	}
+
+	if !matchesKeepersSensitiveDigest(digest, version(tftypes.NewValue(tftypes.String, "3"))) {
+		t.Errorf("expected the same keepers to match %q", digest)
+	}
+	if !matchesKeepersSensitiveDigest(digest, version(tftypes.NewValue(tftypes.Number, 3))) {
+		t.Errorf("expected the retyped keepers to match %q", digest)
+	}
+	if matchesKeepersSensitiveDigest(digest, version(tftypes.NewValue(tftypes.String, "4"))) {
+		t.Errorf("expected changed keepers not to match %q", digest)
+	}
+	if matchesKeepersSensitiveDigest("sha256:zz:00", version(tftypes.NewValue(tftypes.String, "3"))) {
+		t.Error("expected an invalid digest not to match")
+	}
+
+	salted, err := keepersSensitiveDigest(version(tftypes.NewValue(tftypes.String, "3")), []byte("fedcba9876543210"))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if salted == digest {
+		t.Errorf("expected digests with different salts to differ, got %q", digest)
+	}
+}
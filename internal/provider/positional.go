@@ -0,0 +1,204 @@
+// Copyright (c) The Nanoid Provider for Terraform Authors
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"crypto/rand"
+	"fmt"
+	"io"
+	"math"
+	"math/big"
+	"unicode"
+
+	gonanoid "github.com/matoous/go-nanoid"
+)
+
+// positionalAlphabets describes the alphabet each position of an id is
+// sampled from.
+type positionalAlphabets struct {
+	alphabet []rune
+	// first and last replace the alphabet at the first and last positions when set.
+	first []rune
+	last  []rune
+	// noRepeatRun excludes the previous character from the candidates of a position.
+	noRepeatRun bool
+	// caseInsensitive treats the upper and lower case of a character as one
+	// symbol, for noRepeatRun and the entropy.
+	caseInsensitive bool
+	// random is the stream ids are derived from when set, instead of being
+	// generated randomly.
+	random io.Reader
+}
+
+// symbol returns the symbol a character stands for, its case folded when the
+// alphabets are case insensitive.
+func (p *positionalAlphabets) symbol(r rune) rune {
+	if !p.caseInsensitive {
+		return r
+	}
+
+	// The smallest character of the case folding orbit represents all of them.
+	folded := r
+	for f := unicode.SimpleFold(r); f != r; f = unicode.SimpleFold(f) {
+		if f < folded {
+			folded = f
+		}
+	}
+
+	return folded
+}
+
+// at returns the alphabet of the position i of an id of the given length.
+func (p *positionalAlphabets) at(i int, length int) []rune {
+	switch {
+	case i == 0 && p.first != nil:
+		return p.first
+	case i == length-1 && p.last != nil:
+		return p.last
+	default:
+		return p.alphabet
+	}
+}
+
+// candidates returns the characters of the position i that may follow prev.
+func (p *positionalAlphabets) candidates(i int, length int, prev rune, hasPrev bool) []rune {
+	alphabet := p.at(i, length)
+	if !p.noRepeatRun || !hasPrev {
+		return alphabet
+	}
+
+	candidates := make([]rune, 0, len(alphabet))
+	for _, r := range alphabet {
+		if p.symbol(r) != p.symbol(prev) {
+			candidates = append(candidates, r)
+		}
+	}
+
+	return candidates
+}
+
+// generate samples each position of an id from its own alphabet, so that no
+// id is ever rejected.
+func (p *positionalAlphabets) generate(length int) (string, error) {
+	id := make([]rune, 0, length)
+	for i := 0; i < length; i++ {
+		var prev rune
+		if i > 0 {
+			prev = id[i-1]
+		}
+		candidates := p.candidates(i, length, prev, i > 0)
+		if len(candidates) == 0 {
+			return "", fmt.Errorf("no character of the alphabet of position %d differs from the character %q before it", i, prev)
+		}
+
+		c, err := sampleRune(candidates, p.random)
+		if err != nil {
+			return "", err
+		}
+		id = append(id, c)
+	}
+
+	return string(id), nil
+}
+
+// entropyBits returns the Shannon entropy, in bits, of the ids of the given
+// length sampled by generate, read as sequences of symbols.
+func (p *positionalAlphabets) entropyBits(length int) float64 {
+	// The distribution of the symbol of the previous position, only used
+	// when the previous symbol is excluded from the candidates.
+	var dist map[rune]float64
+	bits := 0.0
+	for i := 0; i < length; i++ {
+		alphabet := p.at(i, length)
+		counts := map[rune]float64{}
+		for _, r := range alphabet {
+			counts[p.symbol(r)]++
+		}
+		n := float64(len(alphabet))
+		if n == 0 {
+			dist = map[rune]float64{}
+			continue
+		}
+		s := 0.0
+		for _, k := range counts {
+			s += xlog2x(k)
+		}
+
+		next := make(map[rune]float64, len(counts))
+		if i == 0 || !p.noRepeatRun {
+			bits += math.Log2(n) - s/n
+			for r, k := range counts {
+				next[r] = k / n
+			}
+			dist = next
+			continue
+		}
+
+		// Excluding the previous symbol c leaves n - k(c) candidates, and
+		// the probability of r following c is k(r) / (n - k(c)) for r != c.
+		t := 0.0
+		for c, weight := range dist {
+			k := counts[c]
+			if n == k {
+				continue
+			}
+			bits += weight * (math.Log2(n-k) - (s-xlog2x(k))/(n-k))
+			t += weight / (n - k)
+		}
+		for r, k := range counts {
+			own := 0.0
+			if weight, ok := dist[r]; ok && n > k {
+				own = weight / (n - k)
+			}
+			next[r] = k * (t - own)
+		}
+		dist = next
+	}
+
+	return bits
+}
+
+// xlog2x returns x*log2(x), which is 0 for x = 0.
+func xlog2x(x float64) float64 {
+	if x == 0 {
+		return 0
+	}
+
+	return x * math.Log2(x)
+}
+
+// sampleRune returns a uniformly random character of the candidates, read
+// from random when set, or else with the nanoid engine when it supports the
+// number of candidates.
+func sampleRune(candidates []rune, random io.Reader) (rune, error) {
+	if random == nil && len(candidates) <= 255 {
+		c, err := gonanoid.Generate(string(candidates), 1)
+		if err != nil {
+			return 0, err
+		}
+		return []rune(c)[0], nil
+	}
+
+	i, err := randomInt(random, len(candidates))
+	if err != nil {
+		return 0, err
+	}
+
+	return candidates[i], nil
+}
+
+// randomInt returns a uniformly random integer in [0, n), read from random
+// when set.
+func randomInt(random io.Reader, n int) (int, error) {
+	if random != nil {
+		return uniformInt(random, n)
+	}
+
+	i, err := rand.Int(rand.Reader, big.NewInt(int64(n)))
+	if err != nil {
+		return 0, err
+	}
+
+	return int(i.Int64()), nil
+}
@@ -0,0 +1,87 @@
+// Copyright (c) The Nanoid Provider for Terraform Authors
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestPositionalAlphabets_EntropyBits(t *testing.T) {
+	letters := []rune("abcdefghijklmnopqrstuvwxyz")
+	cases := []struct {
+		name      string
+		positions positionalAlphabets
+		length    int
+		expected  float64
+	}{
+		{"uniform", positionalAlphabets{alphabet: []rune(DEFAULT_ID_ALPHABET)}, 21, 21 * 6},
+		{"first", positionalAlphabets{alphabet: []rune(DEFAULT_DNS_ALPHABET), first: letters}, 10, math.Log2(26) + 9*math.Log2(36)},
+		{"first and last", positionalAlphabets{alphabet: []rune(DEFAULT_DNS_ALPHABET), first: letters, last: []rune("0123456789")}, 10, math.Log2(26) + 8*math.Log2(36) + math.Log2(10)},
+		{"no repeat run", positionalAlphabets{alphabet: []rune(DEFAULT_DNS_ALPHABET), noRepeatRun: true}, 10, math.Log2(36) + 9*math.Log2(35)},
+		{"no repeat run with disjoint first", positionalAlphabets{alphabet: []rune("0123456789"), first: letters, noRepeatRun: true}, 3, math.Log2(26) + math.Log2(10) + math.Log2(9)},
+		// The first character decides the others.
+		{"no repeat run with two characters", positionalAlphabets{alphabet: []rune("ab"), noRepeatRun: true}, 5, 1},
+		// After "a", the last position has one candidate, after "c" two.
+		{"no repeat run with overlap", positionalAlphabets{alphabet: []rune("abc"), first: []rune("ac"), last: []rune("ab"), noRepeatRun: true}, 2, 1 + 0.5*0 + 0.5*1},
+		{"duplicates", positionalAlphabets{alphabet: []rune("aab")}, 1, math.Log2(3) - 2.0/3},
+		// 26 letters in both cases and 12 other characters.
+		{"case insensitive", positionalAlphabets{alphabet: []rune(DEFAULT_ID_ALPHABET), caseInsensitive: true}, 21, 21 * (6 - 52.0/64)},
+		// The first symbol decides the second.
+		{"case insensitive no repeat run", positionalAlphabets{alphabet: []rune("aAb"), noRepeatRun: true, caseInsensitive: true}, 2, math.Log2(3) - 2.0/3},
+	}
+
+	for _, c := range cases {
+		bits := c.positions.entropyBits(c.length)
+		if math.Abs(bits-c.expected) > 1e-9 {
+			t.Errorf("%s: expected %f bits, got %f", c.name, c.expected, bits)
+		}
+	}
+}
+
+func TestPositionalAlphabets_Generate(t *testing.T) {
+	p := positionalAlphabets{
+		alphabet:    []rune("ab"),
+		first:       []rune("xyz"),
+		last:        []rune("0"),
+		noRepeatRun: true,
+	}
+
+	for i := 0; i < 100; i++ {
+		id, err := p.generate(8)
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		if !strings.ContainsAny(id[:1], "xyz") || !strings.HasSuffix(id, "0") {
+			t.Fatalf("unexpected id %q", id)
+		}
+		for j := 1; j < len(id); j++ {
+			if id[j] == id[j-1] {
+				t.Fatalf("the id %q repeats the character %q", id, id[j])
+			}
+		}
+		if id[1:7] != "ababab" && id[1:7] != "bababa" {
+			t.Fatalf("unexpected id %q", id)
+		}
+	}
+
+	p = positionalAlphabets{alphabet: []rune("aAbB"), noRepeatRun: true, caseInsensitive: true}
+	for i := 0; i < 100; i++ {
+		id, err := p.generate(8)
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		for j := 1; j < len(id); j++ {
+			if strings.EqualFold(id[j:j+1], id[j-1:j]) {
+				t.Fatalf("the id %q repeats the symbol %q", id, id[j])
+			}
+		}
+	}
+
+	p = positionalAlphabets{alphabet: []rune("a"), noRepeatRun: true}
+	if _, err := p.generate(2); err == nil {
+		t.Errorf("expected an error when no character can follow the previous one")
+	}
+}
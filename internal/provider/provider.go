@@ -5,14 +5,21 @@ package provider
 
 import (
 	"context"
+	"fmt"
+	"sync"
 
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
 	"github.com/hashicorp/terraform-plugin-framework/function"
 	"github.com/hashicorp/terraform-plugin-framework/provider"
 	"github.com/hashicorp/terraform-plugin-framework/provider/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
 )
 
+const DEFAULT_MAX_ID_LENGTH = 64
+
 // Ensure NanoidProvider satisfies various provider interfaces.
 var _ provider.Provider = &NanoidProvider{}
 var _ provider.ProviderWithFunctions = &NanoidProvider{}
@@ -26,9 +33,27 @@ type NanoidProvider struct {
 }
 
 // NanoidProviderModel describes the provider data model.
-type NanoidProviderModel struct{}
+type NanoidProviderModel struct {
+	MaxIdLength types.Int64 `tfsdk:"max_id_length"`
+}
+
+// NanoidProviderData is shared by the resources of a provider process.
+type NanoidProviderData struct {
+	mu sync.Mutex
+
+	// maxIdLength is the maximum length of the ids generated by nanoid_id resources.
+	maxIdLength int64
+
+	// ports holds the ports allocated by nanoid_port resources, by pool.
+	ports map[string]map[int64]bool
 
-type NanoidProviderData struct{}
+	// sequences holds the highest number handed out by nanoid_sequence
+	// resources, by pool.
+	sequences map[string]int64
+
+	// macs holds the addresses allocated by nanoid_mac_address resources, by pool.
+	macs map[string]map[string]bool
+}
 
 func (p *NanoidProvider) Metadata(ctx context.Context, req provider.MetadataRequest, resp *provider.MetadataResponse) {
 	resp.TypeName = "nanoid"
@@ -38,6 +63,16 @@ func (p *NanoidProvider) Metadata(ctx context.Context, req provider.MetadataRequ
 func (p *NanoidProvider) Schema(ctx context.Context, req provider.SchemaRequest, resp *provider.SchemaResponse) {
 	resp.Schema = schema.Schema{
 		MarkdownDescription: "Nanoid provider provides an interface to the go-nanoid library to generate unique resource identifiers.",
+		Attributes: map[string]schema.Attribute{
+			"max_id_length": schema.Int64Attribute{
+				MarkdownDescription: fmt.Sprintf("The maximum `length` and `max_length` of `nanoid_id` resources, and `length` of `nanoid_pair` resources, and of the ids they import.\n"+
+					"The default value is %d.", DEFAULT_MAX_ID_LENGTH),
+				Optional: true,
+				Validators: []validator.Int64{
+					int64validator.AtLeast(1),
+				},
+			},
+		},
 	}
 }
 
@@ -48,7 +83,15 @@ func (p *NanoidProvider) Configure(ctx context.Context, req provider.ConfigureRe
 		return
 	}
 
-	providerData := NanoidProviderData{}
+	providerData := NanoidProviderData{
+		maxIdLength: DEFAULT_MAX_ID_LENGTH,
+		ports:       make(map[string]map[int64]bool),
+		macs:        make(map[string]map[string]bool),
+		sequences:   make(map[string]int64),
+	}
+	if !data.MaxIdLength.IsNull() && !data.MaxIdLength.IsUnknown() {
+		providerData.maxIdLength = data.MaxIdLength.ValueInt64()
+	}
 	resp.DataSourceData = &providerData
 	resp.ResourceData = &providerData
 }
@@ -57,6 +100,14 @@ func (p *NanoidProvider) Resources(ctx context.Context) []func() resource.Resour
 	return []func() resource.Resource{
 		NewIdResource,
 		NewDnsResource,
+		NewCodeResource,
+		NewSequenceResource,
+		NewShuffleResource,
+		NewPortResource,
+		NewIpv6UlaResource,
+		NewMacAddressResource,
+		NewSqidResource,
+		NewPairResource,
 	}
 }
 
@@ -65,7 +116,12 @@ func (p *NanoidProvider) DataSources(ctx context.Context) []func() datasource.Da
 }
 
 func (p *NanoidProvider) Functions(ctx context.Context) []func() function.Function {
-	return []func() function.Function{}
+	return []func() function.Function{
+		NewVerifyChecksumFunction,
+		NewNormalizeCodeFunction,
+		NewSqidsEncodeFunction,
+		NewSqidsDecodeFunction,
+	}
 }
 
 func New(version string) func() provider.Provider {
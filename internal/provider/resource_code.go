@@ -0,0 +1,400 @@
+// Copyright (c) The Nanoid Provider for Terraform Authors
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64default"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/mapplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	gonanoid "github.com/matoous/go-nanoid"
+)
+
+// DEFAULT_CODE_ALPHABET is the Crockford base32 alphabet, which excludes I, L, O and U.
+const DEFAULT_CODE_ALPHABET = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+const DEFAULT_CODE_GROUP_SIZE = 4
+const DEFAULT_CODE_GROUPS = 3
+const DEFAULT_CODE_SEPARATOR = "-"
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ resource.Resource = &CodeResource{}
+var _ resource.ResourceWithImportState = &CodeResource{}
+var _ resource.ResourceWithValidateConfig = &CodeResource{}
+
+func NewCodeResource() resource.Resource {
+	return &CodeResource{}
+}
+
+// CodeResource defines the resource implementation.
+type CodeResource struct{}
+
+// CodeResourceModel describes the resource data model.
+type CodeResourceModel struct {
+	Id                     types.String  `tfsdk:"id"`
+	Alphabet               types.String  `tfsdk:"alphabet"`
+	GroupSize              types.Int64   `tfsdk:"group_size"`
+	Groups                 types.Int64   `tfsdk:"groups"`
+	Separator              types.String  `tfsdk:"separator"`
+	Keepers                types.Map     `tfsdk:"keepers"`
+	KeepersSensitive       types.Dynamic `tfsdk:"keepers_sensitive"`
+	KeepersSensitiveDigest types.String  `tfsdk:"keepers_sensitive_digest"`
+	Normalized             types.String  `tfsdk:"normalized"`
+}
+
+func (d *CodeResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_code"
+}
+
+func (d *CodeResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "The code resource generates grouped codes, such as `7KQ4-M9XD-2HPR`, that are intended to be read and typed by humans, " +
+			"for example license or enrollment codes.\n\n" +
+			"By default, the codes use the Crockford base32 alphabet which excludes the ambiguous characters `I`, `L`, `O` and `U`. " +
+			"Use the `normalize_code` function to normalize codes typed by humans back to their canonical form.",
+		Attributes: map[string]schema.Attribute{
+			"alphabet": schema.StringAttribute{
+				MarkdownDescription: fmt.Sprintf("Supply your own list of characters to use for code generation.\n"+
+					"Should be between 1 and 255 characters long.\n"+
+					"The default value is `%q`.", DEFAULT_CODE_ALPHABET),
+				Optional: true,
+				Computed: true,
+				Default:  stringdefault.StaticString(DEFAULT_CODE_ALPHABET),
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+					stringplanmodifier.UseStateForUnknown(),
+				},
+				Validators: []validator.String{
+					stringvalidator.LengthBetween(1, 255),
+				},
+			},
+
+			"group_size": schema.Int64Attribute{
+				MarkdownDescription: fmt.Sprintf("The number of characters in each group.\nShould be between 1 and 16.\nThe default value is %d.", DEFAULT_CODE_GROUP_SIZE),
+				Optional:            true,
+				Computed:            true,
+				Default:             int64default.StaticInt64(DEFAULT_CODE_GROUP_SIZE),
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.RequiresReplace(),
+					int64planmodifier.UseStateForUnknown(),
+				},
+				Validators: []validator.Int64{
+					int64validator.Between(1, 16),
+				},
+			},
+
+			"groups": schema.Int64Attribute{
+				MarkdownDescription: fmt.Sprintf("The number of groups in the code.\nShould be between 1 and 16.\nThe default value is %d.", DEFAULT_CODE_GROUPS),
+				Optional:            true,
+				Computed:            true,
+				Default:             int64default.StaticInt64(DEFAULT_CODE_GROUPS),
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.RequiresReplace(),
+					int64planmodifier.UseStateForUnknown(),
+				},
+				Validators: []validator.Int64{
+					int64validator.Between(1, 16),
+				},
+			},
+
+			"separator": schema.StringAttribute{
+				MarkdownDescription: fmt.Sprintf("The string placed between groups.\n"+
+					"Should be at most 4 characters long and must not contain characters of the alphabet.\n"+
+					"The default value is `%q`.", DEFAULT_CODE_SEPARATOR),
+				Optional: true,
+				Computed: true,
+				Default:  stringdefault.StaticString(DEFAULT_CODE_SEPARATOR),
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+					stringplanmodifier.UseStateForUnknown(),
+				},
+				Validators: []validator.String{
+					stringvalidator.LengthAtMost(4),
+				},
+			},
+
+			"keepers": schema.MapAttribute{
+				Description: "Arbitrary map of values that, when changed, will trigger recreation of " +
+					"resource. See [the main provider documentation](../index.html) for more information.",
+				ElementType: types.StringType,
+				Optional:    true,
+				PlanModifiers: []planmodifier.Map{
+					mapplanmodifier.RequiresReplaceIfConfigured(),
+				},
+			},
+
+			"keepers_sensitive": schema.DynamicAttribute{
+				MarkdownDescription: "Arbitrary sensitive values, such as credential versions, that, when changed, will trigger recreation of resource. " +
+					"The values are write-only: only their salted digest is stored, in `keepers_sensitive_digest`. Write-only attributes require Terraform 1.11 or later.",
+				Optional:  true,
+				Sensitive: true,
+				WriteOnly: true,
+			},
+
+			"keepers_sensitive_digest": schema.StringAttribute{
+				MarkdownDescription: "The salted SHA-256 digest of `keepers_sensitive`, of the form `sha256:<salt>:<digest>`, " +
+					"against which their configured values are compared.",
+				Computed: true,
+				PlanModifiers: []planmodifier.String{
+					keepersSensitiveDigestModifier{requiresReplace: true},
+				},
+			},
+
+			"id": schema.StringAttribute{
+				MarkdownDescription: "The generated code, with its groups joined by the separator.",
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+
+			"normalized": schema.StringAttribute{
+				MarkdownDescription: "The generated code without separators.",
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+		},
+	}
+}
+
+func (d *CodeResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	_, ok := req.ProviderData.(*NanoidProviderData)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *provider.NanoidProviderData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+}
+
+func (r *CodeResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data CodeResourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	alphabet := data.Alphabet.ValueString()
+	if data.Alphabet.IsNull() {
+		alphabet = DEFAULT_CODE_ALPHABET
+	}
+
+	groupSize := data.GroupSize.ValueInt64()
+	if data.GroupSize.IsNull() {
+		groupSize = DEFAULT_CODE_GROUP_SIZE
+	}
+
+	groups := data.Groups.ValueInt64()
+	if data.Groups.IsNull() {
+		groups = DEFAULT_CODE_GROUPS
+	}
+
+	separator := data.Separator.ValueString()
+	if data.Separator.IsNull() {
+		separator = DEFAULT_CODE_SEPARATOR
+	}
+
+	raw, err := gonanoid.Generate(alphabet, int(groupSize*groups))
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to generate id", fmt.Sprintf("Failed to generate id: %s.", err))
+		return
+	}
+
+	runes := []rune(raw)
+	parts := make([]string, 0, groups)
+	for i := int64(0); i < groups; i++ {
+		parts = append(parts, string(runes[i*groupSize:(i+1)*groupSize]))
+	}
+
+	data.Id = types.StringValue(strings.Join(parts, separator))
+	data.Normalized = types.StringValue(raw)
+	data.Alphabet = types.StringValue(alphabet)
+	data.GroupSize = types.Int64Value(groupSize)
+	data.Groups = types.Int64Value(groups)
+	data.Separator = types.StringValue(separator)
+	resp.Diagnostics.Append(setKeepersSensitiveDigest(ctx, req.Config, &data.KeepersSensitiveDigest)...)
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *CodeResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var data CodeResourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if data.Alphabet.IsUnknown() || data.Separator.IsUnknown() {
+		return
+	}
+
+	alphabet := data.Alphabet.ValueString()
+	if data.Alphabet.IsNull() {
+		alphabet = DEFAULT_CODE_ALPHABET
+	}
+
+	separator := data.Separator.ValueString()
+	if data.Separator.IsNull() {
+		separator = DEFAULT_CODE_SEPARATOR
+	}
+
+	if err := validateCodeSeparator(alphabet, separator); err != nil {
+		resp.Diagnostics.AddAttributeError(path.Root("separator"), "Invalid separator", fmt.Sprintf("The %s.", err))
+	}
+}
+
+func (d *CodeResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data CodeResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *CodeResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var data CodeResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	// Only the keepers change in place, when removed.
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("keepers"), &data.Keepers)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(setKeepersSensitiveDigest(ctx, req.Config, &data.KeepersSensitiveDigest)...)
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *CodeResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var data CodeResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+func (r *CodeResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	id := req.ID
+	parts := strings.Split(id, DEFAULT_CODE_SEPARATOR)
+	if len(parts) > 16 {
+		resp.Diagnostics.AddError("Invalid id", "The id must have at most 16 groups.")
+		return
+	}
+
+	groupSize := len(parts[0])
+	for _, part := range parts {
+		if len(part) != groupSize || groupSize < 1 || groupSize > 16 {
+			resp.Diagnostics.AddError("Invalid id", fmt.Sprintf("The id must be made of groups of 1 to 16 characters of the same length, separated by %q.", DEFAULT_CODE_SEPARATOR))
+			return
+		}
+		if strings.Trim(part, DEFAULT_CODE_ALPHABET) != "" {
+			resp.Diagnostics.AddError("Invalid id", fmt.Sprintf("The id must only contain characters of the alphabet %q.", DEFAULT_CODE_ALPHABET))
+			return
+		}
+	}
+
+	state := &CodeResourceModel{
+		Id:                     types.StringValue(id),
+		Alphabet:               types.StringValue(DEFAULT_CODE_ALPHABET),
+		GroupSize:              types.Int64Value(int64(groupSize)),
+		Groups:                 types.Int64Value(int64(len(parts))),
+		Separator:              types.StringValue(DEFAULT_CODE_SEPARATOR),
+		Keepers:                types.MapNull(types.StringType),
+		KeepersSensitive:       types.DynamicNull(),
+		KeepersSensitiveDigest: types.StringNull(),
+		Normalized:             types.StringValue(strings.Join(parts, "")),
+	}
+
+	diags := resp.State.Set(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+// validateCodeSeparator checks that a separator can be told apart from the
+// characters of the alphabet.
+func validateCodeSeparator(alphabet, separator string) error {
+	if separator != "" && strings.ContainsAny(separator, alphabet) {
+		return fmt.Errorf("separator %q must not contain characters of the alphabet", separator)
+	}
+
+	return nil
+}
+
+// normalizeCode converts a code typed by a human to its canonical form:
+// separators and whitespace are removed and every other character is mapped
+// to the character of the alphabet it stands for, see codeCharacter.
+func normalizeCode(code, alphabet, separator string) (string, error) {
+	var b strings.Builder
+	position := 0
+	for len(code) > 0 {
+		if separator != "" && strings.HasPrefix(code, separator) {
+			code = code[len(separator):]
+			position += utf8.RuneCountInString(separator)
+			continue
+		}
+
+		r, size := utf8.DecodeRuneInString(code)
+		code = code[size:]
+		position++
+		if unicode.IsSpace(r) {
+			continue
+		}
+
+		c, ok := codeCharacter(r, alphabet)
+		if !ok {
+			return "", fmt.Errorf("the character %q at position %d is not a valid code character", r, position-1)
+		}
+		b.WriteRune(c)
+	}
+
+	return b.String(), nil
+}
+
+// codeCharacter returns the character of the alphabet that r stands for: r
+// itself, else r in the other case, else the digit that the confusable
+// Crockford letters I, L and O are read as, 1, 1 and 0.
+func codeCharacter(r rune, alphabet string) (rune, bool) {
+	for _, c := range []rune{r, unicode.ToUpper(r), unicode.ToLower(r)} {
+		if strings.ContainsRune(alphabet, c) {
+			return c, true
+		}
+	}
+
+	var c rune
+	switch unicode.ToUpper(r) {
+	case 'I', 'L':
+		c = '1'
+	case 'O':
+		c = '0'
+	default:
+		return 0, false
+	}
+
+	return c, strings.ContainsRune(alphabet, c)
+}
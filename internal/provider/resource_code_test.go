@@ -0,0 +1,217 @@
+// Copyright (c) The Nanoid Provider for Terraform Authors
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/plancheck"
+)
+
+func TestAccCodeResource(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCodeResourceConfigEmpty(),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("nanoid_code.test", "group_size", "4"),
+					resource.TestCheckResourceAttr("nanoid_code.test", "groups", "3"),
+					resource.TestCheckResourceAttr("nanoid_code.test", "separator", "-"),
+					resource.TestCheckResourceAttr("nanoid_code.test", "alphabet", "0123456789ABCDEFGHJKMNPQRSTVWXYZ"),
+					resource.TestMatchResourceAttr("nanoid_code.test", "id", regexp.MustCompile(`^[0-9A-HJKMNP-TV-Z]{4}-[0-9A-HJKMNP-TV-Z]{4}-[0-9A-HJKMNP-TV-Z]{4}$`)),
+					resource.TestMatchResourceAttr("nanoid_code.test", "normalized", regexp.MustCompile(`^[0-9A-HJKMNP-TV-Z]{12}$`)),
+				),
+			},
+			{
+				ResourceName:      "nanoid_code.test",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func TestAccCodeResource_WithGroups(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCodeResourceConfig(5, 2, " "),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("nanoid_code.test", "group_size", "5"),
+					resource.TestCheckResourceAttr("nanoid_code.test", "groups", "2"),
+					resource.TestMatchResourceAttr("nanoid_code.test", "id", regexp.MustCompile(`^[0-9A-HJKMNP-TV-Z]{5} [0-9A-HJKMNP-TV-Z]{5}$`)),
+					resource.TestCheckResourceAttrWith("nanoid_code.test", "normalized", testCheckLen(10)),
+				),
+			},
+		},
+	})
+}
+
+func TestAccCodeResource_SeparatorInAlphabet(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      testAccCodeResourceConfig(4, 3, "A"),
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile(`must not contain characters of the alphabet`),
+			},
+		},
+	})
+}
+
+func TestAccCodeResource_Keepers(t *testing.T) {
+	var id string
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCodeResourceConfigKeepers(`{ release = "1.0" }`),
+				Check:  testExtractResourceAttr("nanoid_code.test", "id", &id),
+			},
+			{
+				Config: testAccCodeResourceConfigKeepers(`{ release = "1.1" }`),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("nanoid_code.test", plancheck.ResourceActionReplace),
+					},
+				},
+				Check: testCheckResourceAttrChanged("nanoid_code.test", "id", &id),
+			},
+			{
+				Config: testAccCodeResourceConfigEmpty(),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("nanoid_code.test", plancheck.ResourceActionUpdate),
+					},
+				},
+				Check: resource.TestCheckNoResourceAttr("nanoid_code.test", "keepers"),
+			},
+		},
+	})
+}
+
+func TestAccCodeResource_KeepersSensitive(t *testing.T) {
+	var id, digest string
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCodeResourceConfigKeepersSensitive(`{ password_version = "1" }`),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckNoResourceAttr("nanoid_code.test", "keepers_sensitive"),
+					resource.TestMatchResourceAttr("nanoid_code.test", "keepers_sensitive_digest", regexp.MustCompile(`^sha256:[0-9a-f]{32}:[0-9a-f]{64}$`)),
+					testExtractResourceAttr("nanoid_code.test", "id", &id),
+					testExtractResourceAttr("nanoid_code.test", "keepers_sensitive_digest", &digest),
+				),
+			},
+			{
+				Config: testAccCodeResourceConfigKeepersSensitive(`{ password_version = 1 }`),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectEmptyPlan(),
+					},
+				},
+			},
+			{
+				Config: testAccCodeResourceConfigKeepersSensitive(`{ password_version = "2" }`),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("nanoid_code.test", plancheck.ResourceActionReplace),
+						expectReplacePaths("nanoid_code.test", "keepers_sensitive_digest"),
+					},
+				},
+				Check: resource.ComposeAggregateTestCheckFunc(
+					testCheckResourceAttrChanged("nanoid_code.test", "id", &id),
+					testCheckResourceAttrChanged("nanoid_code.test", "keepers_sensitive_digest", &digest),
+				),
+			},
+			{
+				Config: testAccCodeResourceConfigEmpty(),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("nanoid_code.test", plancheck.ResourceActionUpdate),
+					},
+				},
+				Check: resource.TestCheckNoResourceAttr("nanoid_code.test", "keepers_sensitive_digest"),
+			},
+		},
+	})
+}
+
+// expectReplacePaths checks the paths, dot separated, at which a planned
+// change forces the replacement of a resource.
+func expectReplacePaths(address string, expected ...string) plancheck.PlanCheck {
+	return replacePathsCheck{address: address, expected: expected}
+}
+
+type replacePathsCheck struct {
+	address  string
+	expected []string
+}
+
+func (c replacePathsCheck) CheckPlan(ctx context.Context, req plancheck.CheckPlanRequest, resp *plancheck.CheckPlanResponse) {
+	for _, change := range req.Plan.ResourceChanges {
+		if change.Address != c.address {
+			continue
+		}
+
+		var paths []string
+		for _, p := range change.Change.ReplacePaths {
+			steps := make([]string, 0)
+			for _, step := range p.([]any) {
+				steps = append(steps, fmt.Sprint(step))
+			}
+			paths = append(paths, strings.Join(steps, "."))
+		}
+		if !slices.Equal(paths, c.expected) {
+			resp.Error = fmt.Errorf("%s: expected replace paths %v, got %v", c.address, c.expected, paths)
+		}
+		return
+	}
+	resp.Error = fmt.Errorf("%s: no planned change", c.address)
+}
+
+func testAccCodeResourceConfig(groupSize int, groups int, separator string) string {
+	return fmt.Sprintf(`
+resource "nanoid_code" "test" {
+  group_size = %d
+  groups     = %d
+  separator  = %q
+}
+`, groupSize, groups, separator)
+}
+
+func testAccCodeResourceConfigEmpty() string {
+	return `resource "nanoid_code" "test" {}`
+}
+
+func testAccCodeResourceConfigKeepers(keepers string) string {
+	return fmt.Sprintf(`
+resource "nanoid_code" "test" {
+  keepers = %s
+}
+`, keepers)
+}
+
+func testAccCodeResourceConfigKeepersSensitive(keepers string) string {
+	return fmt.Sprintf(`
+resource "nanoid_code" "test" {
+  keepers_sensitive = %s
+}
+`, keepers)
+}
@@ -6,18 +6,24 @@ package provider
 import (
 	"context"
 	"fmt"
+	"regexp"
+	"time"
 
+	"github.com/hashicorp/terraform-plugin-framework-validators/float64validator"
 	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/boolplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/float64planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64default"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
-	"github.com/hashicorp/terraform-plugin-framework/resource/schema/mapplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
-	gonanoid "github.com/matoous/go-nanoid"
 )
 
 const DEFAULT_DNS_ALPHABET = "0123456789abcdefghijklmnopqrstuvwxyz"
@@ -25,7 +31,10 @@ const DEFAULT_DNS_LENGTH = 10
 
 // Ensure provider defined types fully satisfy framework interfaces.
 var _ resource.Resource = &DnsResource{}
+var _ resource.ResourceWithUpgradeState = &DnsResource{}
 var _ resource.ResourceWithImportState = &DnsResource{}
+var _ resource.ResourceWithConfigValidators = &DnsResource{}
+var _ resource.ResourceWithModifyPlan = &DnsResource{}
 
 func NewDnsResource() resource.Resource {
 	return &DnsResource{}
@@ -36,9 +45,23 @@ type DnsResource struct{}
 
 // DnsResourceModel describes the data source data model.
 type DnsResourceModel struct {
-	Id      types.String `tfsdk:"id"`
-	Keepers types.Map    `tfsdk:"keepers"`
-	Length  types.Int64  `tfsdk:"length"`
+	Id                     types.String  `tfsdk:"id"`
+	Keepers                types.Dynamic `tfsdk:"keepers"`
+	KeepersSensitive       types.Dynamic `tfsdk:"keepers_sensitive"`
+	KeepersSensitiveDigest types.String  `tfsdk:"keepers_sensitive_digest"`
+	Labels                 types.Map     `tfsdk:"labels"`
+	Seed                   types.String  `tfsdk:"seed"`
+	Salt                   types.String  `tfsdk:"salt"`
+	Length                 types.Int64   `tfsdk:"length"`
+	FirstCharAlphabet      types.String  `tfsdk:"first_char_alphabet"`
+	LastCharAlphabet       types.String  `tfsdk:"last_char_alphabet"`
+	NoRepeatRun            types.Bool    `tfsdk:"no_repeat_run"`
+	EntropyBits            types.Float64 `tfsdk:"entropy_bits"`
+	MinEntropyBits         types.Float64 `tfsdk:"min_entropy_bits"`
+	CreatedAt              types.String  `tfsdk:"created_at"`
+	Algorithm              types.String  `tfsdk:"algorithm"`
+	Generation             types.Int64   `tfsdk:"generation"`
+	RegenerateInPlace      types.Bool    `tfsdk:"regenerate_in_place"`
 }
 
 func (d *DnsResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
@@ -47,10 +70,13 @@ func (d *DnsResource) Metadata(ctx context.Context, req resource.MetadataRequest
 
 func (d *DnsResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
 	resp.Schema = schema.Schema{
+		Version: KEEPERS_SCHEMA_VERSION,
 		MarkdownDescription: fmt.Sprintf("The dns resource generates hostname/dns friendly random strings that are intended to be used as unique identifiers for other resources.\n\n"+
 			"The alphabet used is `\"%q\"`\n\n"+
 			"This resource can be used in conjunction with resources that have the `create_before_destroy` lifecycle flag set to avoid conflicts with "+
-			"unique names during the brief period where both the old and new resources exist concurrently.", DEFAULT_DNS_ALPHABET),
+			"unique names during the brief period where both the old and new resources exist concurrently.\n\n"+
+			"Changing `keepers` replaces the resource, unless `regenerate_in_place` is set: the id is then regenerated in place, "+
+			"with `generation` counting the ids the resource has held.", DEFAULT_DNS_ALPHABET),
 		Attributes: map[string]schema.Attribute{
 			"length": schema.Int64Attribute{
 				MarkdownDescription: fmt.Sprintf("The length of the desired nanoid.\nShould be between 1 and 64.\nThe default value is %d.", DEFAULT_ID_LENGTH),
@@ -66,13 +92,165 @@ func (d *DnsResource) Schema(ctx context.Context, req resource.SchemaRequest, re
 				},
 			},
 
-			"keepers": schema.MapAttribute{
-				Description: "Arbitrary map of values that, when changed, will trigger recreation of " +
-					"resource. See [the main provider documentation](../index.html) for more information.",
+			"first_char_alphabet": schema.StringAttribute{
+				MarkdownDescription: "The characters to use for the first character of the id, instead of the dns alphabet, " +
+					"for example `abcdefghijklmnopqrstuvwxyz` for hostnames requiring a leading letter.\n" +
+					"Should only contain characters of the dns alphabet, without duplicates.",
+				Optional: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+				Validators: []validator.String{
+					stringvalidator.RegexMatches(regexp.MustCompile(`^[0-9a-z]+$`), "must only contain characters of the dns alphabet"),
+					validAlphabet(),
+				},
+			},
+
+			"last_char_alphabet": schema.StringAttribute{
+				MarkdownDescription: "The characters to use for the last character of the id, instead of the dns alphabet.\n" +
+					"Should only contain characters of the dns alphabet, without duplicates.",
+				Optional: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+				Validators: []validator.String{
+					stringvalidator.RegexMatches(regexp.MustCompile(`^[0-9a-z]+$`), "must only contain characters of the dns alphabet"),
+					validAlphabet(),
+				},
+			},
+
+			"no_repeat_run": schema.BoolAttribute{
+				MarkdownDescription: "Never generate the same character twice in a row.\n" +
+					"Each character is sampled from the characters of its alphabet that differ from the previous character.\n" +
+					"The default value is `false`.",
+				Optional: true,
+				PlanModifiers: []planmodifier.Bool{
+					boolplanmodifier.RequiresReplace(),
+				},
+			},
+
+			"keepers": schema.DynamicAttribute{
+				Description: "Arbitrary values of any type that, when changed, will trigger recreation of resource, " +
+					"or regenerate the id in place and increment `generation` when `regenerate_in_place` is set. " +
+					"See [the main provider documentation](../index.html) for more information.",
+				Optional: true,
+			},
+
+			"keepers_sensitive": schema.DynamicAttribute{
+				MarkdownDescription: "Arbitrary sensitive values, such as credential versions, that, when changed, will trigger recreation of resource, " +
+					"or regenerate the id in place and increment `generation` when `regenerate_in_place` is set. " +
+					"The values are write-only: only their salted digest is stored, in `keepers_sensitive_digest`. Write-only attributes require Terraform 1.11 or later.",
+				Optional:  true,
+				Sensitive: true,
+				WriteOnly: true,
+			},
+
+			"regenerate_in_place": schema.BoolAttribute{
+				MarkdownDescription: "Regenerate the id in place when `keepers` or `keepers_sensitive` change, incrementing `generation`, " +
+					"instead of replacing the resource.\n" +
+					"Changes are applied in place.\n" +
+					"The default value is `false`.",
+				Optional: true,
+			},
+
+			"keepers_sensitive_digest": schema.StringAttribute{
+				MarkdownDescription: "The salted SHA-256 digest of `keepers_sensitive`, of the form `sha256:<salt>:<digest>`, " +
+					"against which their configured values are compared.",
+				Computed: true,
+				PlanModifiers: []planmodifier.String{
+					keepersSensitiveDigestModifier{requiresReplace: false},
+				},
+			},
+
+			"labels": schema.MapAttribute{
+				MarkdownDescription: "Arbitrary map of metadata, such as an owner, a ticket or a purpose. " +
+					"Unlike `keepers`, changes are applied in place and never regenerate the id.",
 				ElementType: types.StringType,
 				Optional:    true,
-				PlanModifiers: []planmodifier.Map{
-					mapplanmodifier.RequiresReplaceIfConfigured(),
+			},
+
+			"seed": schema.StringAttribute{
+				MarkdownDescription: "A secret from which the id is derived deterministically with HMAC-DRBG, over the seed, `salt`, `keepers`, `keepers_sensitive` and `generation`, " +
+					"instead of being generated randomly. Re-applying with the same seed, salt, keepers and sensitive keepers, for example after the state is lost, " +
+					"reproduces the same id of the first generation. Ids regenerated in place, see `regenerate_in_place`, also derive from their generation, " +
+					"so that changing `keepers` back to earlier values derives a new id rather than a retired one. " +
+					"To recover such an id after the state is lost, also set `generation` to the generation it had, " +
+					"then remove `generation` once it is applied. " +
+					"The seed is write-only and never stored, so setting or changing it alone does not regenerate the id. " +
+					"Write-only attributes require Terraform 1.11 or later.",
+				Optional:  true,
+				Sensitive: true,
+				WriteOnly: true,
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+				},
+			},
+
+			"salt": schema.StringAttribute{
+				MarkdownDescription: "A value mixed into the derivation of the id from `seed`, to derive distinct ids from the same seed. " +
+					"Requires `seed`. Changes force a new id.",
+				Optional: true,
+				Validators: []validator.String{
+					stringvalidator.AlsoRequires(path.MatchRoot("seed")),
+				},
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+
+			"min_entropy_bits": schema.Float64Attribute{
+				MarkdownDescription: "The minimum entropy of the generated id in bits, checked against the combination of the alphabets and `length` " +
+					"when the configuration is validated, so that weak ids are caught before they are generated.",
+				Optional: true,
+				Validators: []validator.Float64{
+					float64validator.AtLeast(0),
+				},
+			},
+
+			"entropy_bits": schema.Float64Attribute{
+				MarkdownDescription: "The entropy of the generated id in bits, given its alphabets and length.",
+				Computed:            true,
+				PlanModifiers: []planmodifier.Float64{
+					float64planmodifier.UseStateForUnknown(),
+				},
+			},
+
+			"created_at": schema.StringAttribute{
+				MarkdownDescription: "The time the id was generated, in RFC 3339 format.\n" +
+					"Null for imported ids and ids generated by earlier versions of the provider.",
+				Computed: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+
+			"algorithm": schema.StringAttribute{
+				MarkdownDescription: "The algorithm that generated the id, `" + ID_ALGORITHM + "` for ids sampled with the nanoid engine from a " +
+					"cryptographically secure random source, or `" + SEEDED_ID_ALGORITHM + "` for ids derived from `seed`.\n" +
+					"Null for imported ids and ids generated by earlier versions of the provider.",
+				Computed: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+
+			"generation": schema.Int64Attribute{
+				MarkdownDescription: "The number of ids the resource has held: 1 for its first id, incremented every time changing `keepers` regenerates the id in place, " +
+					"see `regenerate_in_place`. " +
+					"It does not count replacements: a replacing resource, including one caused by `keepers` without `regenerate_in_place`, " +
+					"is planned without the state of the replaced one and starts again at 1, so set `regenerate_in_place` to count every id forced by `keepers`.\n" +
+					"Ids imported or generated by earlier versions of the provider have no generation, and the ids regenerated for them start at 1.\n" +
+					"Can be set to the generation of an id derived from `seed` to derive it again, for example to recover an id regenerated in place after the state is lost. " +
+					"While set, changing it regenerates the id like changing `keepers`, and changing `keepers` keeps it, " +
+					"so removing it once the id is recovered lets the next regenerations count again.\n" +
+					"Should be at least 1.",
+				Optional: true,
+				Computed: true,
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.UseStateForUnknown(),
+				},
+				Validators: []validator.Int64{
+					int64validator.AtLeast(1),
 				},
 			},
 
@@ -111,20 +289,13 @@ func (r *DnsResource) Create(ctx context.Context, req resource.CreateRequest, re
 		return
 	}
 
-	alphabet := DEFAULT_DNS_ALPHABET
-	length := data.Length.ValueInt64()
-	if data.Length.IsNull() {
-		length = DEFAULT_DNS_LENGTH
-	}
-
-	id, err := gonanoid.Generate(alphabet, int(length))
-	if err != nil {
-		resp.Diagnostics.AddError("Failed to generate id", fmt.Sprintf("Failed to generate id: %s.", err))
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("generation"), &data.Generation)...)
+	resp.Diagnostics.Append(data.generateId(ctx)...)
+	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	data.Id = types.StringValue(id)
-	data.Length = types.Int64Value(length)
+	resp.Diagnostics.Append(setKeepersSensitiveDigest(ctx, req.Config, &data.KeepersSensitiveDigest)...)
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
 }
 
@@ -140,14 +311,49 @@ func (d *DnsResource) Read(ctx context.Context, req resource.ReadRequest, resp *
 
 func (r *DnsResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
 	var data DnsResourceModel
-	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	// The seed and the sensitive keepers are write-only, so they are only in
+	// the configuration.
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("seed"), &data.Seed)...)
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("keepers_sensitive"), &data.KeepersSensitive)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// The id is only regenerated when the keepers changed. Otherwise it is
+	// kept from the state with its creation metadata, so only attributes which
+	// do not change it, such as labels or min_entropy_bits, are applied in place.
+	if data.Id.IsUnknown() {
+		resp.Diagnostics.Append(data.generateId(ctx)...)
+	} else {
+		resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root("created_at"), &data.CreatedAt)...)
+		resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root("algorithm"), &data.Algorithm)...)
+		resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root("generation"), &data.Generation)...)
+	}
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
+	resp.Diagnostics.Append(setKeepersSensitiveDigest(ctx, req.Config, &data.KeepersSensitiveDigest)...)
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
 }
 
+func (r *DnsResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	planKeepersRegeneration(ctx, req, resp, false)
+}
+
+func (r *DnsResource) ConfigValidators(ctx context.Context) []resource.ConfigValidator {
+	return []resource.ConfigValidator{
+		minEntropyBits(func() entropyModel { return &DnsResourceModel{} }),
+	}
+}
+
+func (r *DnsResource) UpgradeState(ctx context.Context) map[int64]resource.StateUpgrader {
+	return map[int64]resource.StateUpgrader{
+		0: keepersStateUpgrader(),
+	}
+}
+
 func (r *DnsResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
 	var data DnsResourceModel
 	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
@@ -165,10 +371,24 @@ func (r *DnsResource) ImportState(ctx context.Context, req resource.ImportStateR
 	}
 
 	state := &DnsResourceModel{
-		Id:      types.StringValue(id),
-		Length:  types.Int64Value(int64(length)),
-		Keepers: types.MapNull(types.StringType),
+		Id:                     types.StringValue(id),
+		Length:                 types.Int64Value(int64(length)),
+		Keepers:                types.DynamicNull(),
+		KeepersSensitive:       types.DynamicNull(),
+		KeepersSensitiveDigest: types.StringNull(),
+		Labels:                 types.MapNull(types.StringType),
+		Seed:                   types.StringNull(),
+		Salt:                   types.StringNull(),
+		FirstCharAlphabet:      types.StringNull(),
+		LastCharAlphabet:       types.StringNull(),
+		NoRepeatRun:            types.BoolNull(),
+		MinEntropyBits:         types.Float64Null(),
+		CreatedAt:              types.StringNull(),
+		Algorithm:              types.StringNull(),
+		Generation:             types.Int64Null(),
+		RegenerateInPlace:      types.BoolNull(),
 	}
+	state.EntropyBits = types.Float64Value(state.positions().entropyBits(length))
 
 	diags := resp.State.Set(ctx, &state)
 	resp.Diagnostics.Append(diags...)
@@ -176,3 +396,69 @@ func (r *DnsResource) ImportState(ctx context.Context, req resource.ImportStateR
 		return
 	}
 }
+
+// generateId generates an id, or derives it from the seed when set, and
+// records it with its creation metadata.
+func (data *DnsResourceModel) generateId(ctx context.Context) (diags diag.Diagnostics) {
+	length := data.Length.ValueInt64()
+	if data.Length.IsNull() {
+		length = DEFAULT_DNS_LENGTH
+	}
+
+	random, diags := seededRandom(ctx, data.Seed, data.Salt, data.Keepers, data.KeepersSensitive, data.Generation)
+	if diags.HasError() {
+		return diags
+	}
+
+	positions := data.positions()
+	positions.random = random
+	id, err := positions.generate(int(length))
+	if err != nil {
+		diags.AddError("Failed to generate id", fmt.Sprintf("Failed to generate id: %s.", err))
+		return diags
+	}
+
+	data.Id = types.StringValue(id)
+	data.Length = types.Int64Value(length)
+	data.EntropyBits = types.Float64Value(positions.entropyBits(int(length)))
+	data.CreatedAt = types.StringValue(time.Now().UTC().Format(time.RFC3339))
+	data.Algorithm = types.StringValue(ID_ALGORITHM)
+	if random != nil {
+		data.Algorithm = types.StringValue(SEEDED_ID_ALGORITHM)
+	}
+
+	return diags
+}
+
+// positions returns the alphabets of the positions of the id.
+func (data *DnsResourceModel) positions() *positionalAlphabets {
+	p := &positionalAlphabets{
+		alphabet:    []rune(DEFAULT_DNS_ALPHABET),
+		noRepeatRun: data.NoRepeatRun.ValueBool(),
+	}
+	if !data.FirstCharAlphabet.IsNull() {
+		p.first = []rune(data.FirstCharAlphabet.ValueString())
+	}
+	if !data.LastCharAlphabet.IsNull() {
+		p.last = []rune(data.LastCharAlphabet.ValueString())
+	}
+
+	return p
+}
+
+func (data *DnsResourceModel) minEntropyBits() types.Float64 {
+	return data.MinEntropyBits
+}
+
+func (data *DnsResourceModel) configuredPositions() (*positionalAlphabets, int, bool) {
+	if data.Length.IsUnknown() || data.FirstCharAlphabet.IsUnknown() || data.LastCharAlphabet.IsUnknown() || data.NoRepeatRun.IsUnknown() {
+		return nil, 0, false
+	}
+
+	length := data.Length.ValueInt64()
+	if data.Length.IsNull() {
+		length = DEFAULT_DNS_LENGTH
+	}
+
+	return data.positions(), int(length), true
+}
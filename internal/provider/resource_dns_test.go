@@ -5,9 +5,12 @@ package provider
 
 import (
 	"fmt"
+	"math"
+	"regexp"
 	"testing"
 
 	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/plancheck"
 )
 
 func TestAccDnsResource(t *testing.T) {
@@ -23,9 +26,10 @@ func TestAccDnsResource(t *testing.T) {
 				),
 			},
 			{
-				ResourceName:      "nanoid_dns.test",
-				ImportState:       true,
-				ImportStateVerify: true,
+				ResourceName:            "nanoid_dns.test",
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"created_at", "algorithm", "generation"},
 			},
 		},
 	})
@@ -44,9 +48,169 @@ func TestAccDnsResource_WithLength(t *testing.T) {
 				),
 			},
 			{
-				ResourceName:      "nanoid_dns.test",
-				ImportState:       true,
-				ImportStateVerify: true,
+				ResourceName:            "nanoid_dns.test",
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"created_at", "algorithm", "generation"},
+			},
+		},
+	})
+}
+
+func TestAccDnsResource_WithPositionalAlphabets(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+resource "nanoid_dns" "test" {
+  first_char_alphabet = "abcdefghijklmnopqrstuvwxyz"
+  no_repeat_run       = true
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestMatchResourceAttr("nanoid_dns.test", "id", regexp.MustCompile(`^[a-z][0-9a-z]{9}$`)),
+					resource.TestCheckResourceAttrWith("nanoid_dns.test", "id", testCheckNoRepeatRun),
+					resource.TestCheckResourceAttrWith("nanoid_dns.test", "entropy_bits", testCheckEntropyBits(math.Log2(26)+9*math.Log2(35))),
+				),
+			},
+			{
+				ResourceName:            "nanoid_dns.test",
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"first_char_alphabet", "no_repeat_run", "entropy_bits", "created_at", "algorithm", "generation"},
+			},
+		},
+	})
+}
+
+func TestAccDnsResource_WithMinEntropyBits(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+resource "nanoid_dns" "test" {
+  min_entropy_bits = 64
+}
+`,
+				ExpectError: regexp.MustCompile(`Set\s+length\s+to\s+at\s+least\s+13`),
+			},
+			{
+				Config: `
+resource "nanoid_dns" "test" {
+  length           = 13
+  min_entropy_bits = 64
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttrWith("nanoid_dns.test", "id", testCheckLen(13)),
+					resource.TestCheckResourceAttrWith("nanoid_dns.test", "entropy_bits", testCheckEntropyBits(13*math.Log2(36))),
+				),
+			},
+		},
+	})
+}
+
+func TestAccDnsResource_InvalidFirstCharAlphabet(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+resource "nanoid_dns" "test" {
+  first_char_alphabet = "-abc"
+}
+`,
+				ExpectError: regexp.MustCompile(`must\s+only\s+contain\s+characters\s+of\s+the\s+dns\s+alphabet`),
+			},
+			{
+				Config: `
+resource "nanoid_dns" "test" {
+  last_char_alphabet = "abca"
+}
+`,
+				ExpectError: regexp.MustCompile(`The\s+character\s+'a'\s+appears\s+at\s+positions\s+0\s+and\s+3`),
+			},
+		},
+	})
+}
+
+func TestAccDnsResource_Generation(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccDnsResourceConfigKeepers("a"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("nanoid_dns.test", "generation", "1"),
+					resource.TestCheckResourceAttr("nanoid_dns.test", "algorithm", ID_ALGORITHM),
+					resource.TestCheckResourceAttrWith("nanoid_dns.test", "created_at", testCheckRFC3339),
+				),
+			},
+			{
+				Config: testAccDnsResourceConfigKeepers("b"),
+				Check:  resource.TestCheckResourceAttr("nanoid_dns.test", "generation", "2"),
+			},
+		},
+	})
+}
+
+func TestAccDnsResource_Labels(t *testing.T) {
+	var id, createdAt string
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccDnsResourceConfigLabels("platform"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("nanoid_dns.test", "labels.owner", "platform"),
+					testExtractResourceAttr("nanoid_dns.test", "id", &id),
+					testExtractResourceAttr("nanoid_dns.test", "created_at", &createdAt),
+				),
+			},
+			{
+				Config: testAccDnsResourceConfigLabels("payments"),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("nanoid_dns.test", plancheck.ResourceActionUpdate),
+					},
+				},
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("nanoid_dns.test", "labels.owner", "payments"),
+					resource.TestCheckResourceAttrPtr("nanoid_dns.test", "id", &id),
+					resource.TestCheckResourceAttrPtr("nanoid_dns.test", "created_at", &createdAt),
+					resource.TestCheckResourceAttr("nanoid_dns.test", "generation", "1"),
+				),
+			},
+			{
+				Config: testAccDnsResourceConfigEmpty(),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckNoResourceAttr("nanoid_dns.test", "labels"),
+					resource.TestCheckResourceAttrPtr("nanoid_dns.test", "id", &id),
+				),
+			},
+		},
+	})
+}
+
+func TestAccDnsResource_Seed(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccDnsResourceConfigSeed(),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckNoResourceAttr("nanoid_dns.test", "seed"),
+					resource.TestCheckResourceAttr("nanoid_dns.test", "id", "aup5uympce"),
+					resource.TestCheckResourceAttr("nanoid_dns.test", "algorithm", SEEDED_ID_ALGORITHM),
+				),
 			},
 		},
 	})
@@ -64,3 +228,37 @@ resource "nanoid_dns" "test" {
 func testAccDnsResourceConfigEmpty() string {
 	return `resource "nanoid_dns" "test" {}`
 }
+
+func testAccDnsResourceConfigKeepers(keeper string) string {
+	return fmt.Sprintf(`
+resource "nanoid_dns" "test" {
+  regenerate_in_place = true
+  keepers = {
+    keeper = %q
+  }
+}
+`, keeper)
+}
+
+func testAccDnsResourceConfigLabels(owner string) string {
+	return fmt.Sprintf(`
+resource "nanoid_dns" "test" {
+  labels = {
+    owner  = %q
+    ticket = "OPS-1234"
+  }
+}
+`, owner)
+}
+
+func testAccDnsResourceConfigSeed() string {
+	return `
+resource "nanoid_dns" "test" {
+  seed = "correct horse battery staple"
+  salt = "eu-west-1"
+  keepers = {
+    env = "prod"
+  }
+}
+`
+}
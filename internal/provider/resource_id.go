@@ -5,43 +5,131 @@ package provider
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
 	"fmt"
+	"math"
+	"math/rand/v2"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
 
+	"github.com/hashicorp/terraform-plugin-framework-validators/float64validator"
 	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
 	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/boolplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/float64planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64default"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
-	"github.com/hashicorp/terraform-plugin-framework/resource/schema/mapplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
-	gonanoid "github.com/matoous/go-nanoid"
 )
 
 const DEFAULT_ID_ALPHABET = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZ_abcdefghijklmnopqrstuvwxyz-"
 const DEFAULT_ID_LENGTH = 21
 
+// MAX_ID_ALPHABET_LENGTH is the maximum number of characters of an alphabet.
+// Alphabets of more than 255 characters are sampled with crypto/rand, as the
+// nanoid engine only supports up to 255 characters.
+const MAX_ID_ALPHABET_LENGTH = 65536
+const DEFAULT_ID_MAX_ATTEMPTS = 1000
+
+// SIMILAR_CHARACTERS holds the characters that are easily confused with one
+// another when read from a screen, removed by exclude_similar.
+const SIMILAR_CHARACTERS = "0O1lI|5S2Z"
+
+const MAX_ID_MAX_ATTEMPTS = 100000
+const MAX_ID_HISTORY_SIZE = 100
+
+// NEXT_ID_PRIVATE_KEY is the private state key of the id drawn in advance by
+// generate_at_plan.
+const NEXT_ID_PRIVATE_KEY = "next_id"
+
+// ID_CONSTRAINT_SAMPLES is the number of ids generated to estimate the
+// probability that an id satisfies the must_match and must_not_match constraints.
+const ID_CONSTRAINT_SAMPLES = 10000
+
+// ID_CONSTRAINT_SAMPLER_SEED seeds the sampled ids, so that a configuration
+// gets the same estimate, and is accepted or rejected alike, on every plan.
+const ID_CONSTRAINT_SAMPLER_SEED = "nanoid_id constraint sampler"
+
+// ID_CONSTRAINT_MIN_SUCCESS is the estimated probability of generating an id
+// within max_attempts below which a configuration is rejected.
+const ID_CONSTRAINT_MIN_SUCCESS = 0.99
+
 // Ensure provider defined types fully satisfy framework interfaces.
 var _ resource.Resource = &IdResource{}
+var _ resource.ResourceWithUpgradeState = &IdResource{}
 var _ resource.ResourceWithImportState = &IdResource{}
+var _ resource.ResourceWithConfigValidators = &IdResource{}
+var _ resource.ResourceWithValidateConfig = &IdResource{}
+var _ resource.ResourceWithModifyPlan = &IdResource{}
 
 func NewIdResource() resource.Resource {
 	return &IdResource{}
 }
 
 // IdResource defines the data source implementation.
-type IdResource struct{}
+type IdResource struct {
+	providerData *NanoidProviderData
+}
 
 // IdResourceModel describes the data source data model.
 type IdResourceModel struct {
-	Id       types.String `tfsdk:"id"`
-	Alphabet types.String `tfsdk:"alphabet"`
-	Keepers  types.Map    `tfsdk:"keepers"`
-	Length   types.Int64  `tfsdk:"length"`
+	Id                     types.String  `tfsdk:"id"`
+	Alphabet               types.String  `tfsdk:"alphabet"`
+	Checksum               types.String  `tfsdk:"checksum"`
+	Keepers                types.Dynamic `tfsdk:"keepers"`
+	KeepersSensitive       types.Dynamic `tfsdk:"keepers_sensitive"`
+	KeepersSensitiveDigest types.String  `tfsdk:"keepers_sensitive_digest"`
+	Labels                 types.Map     `tfsdk:"labels"`
+	Seed                   types.String  `tfsdk:"seed"`
+	Salt                   types.String  `tfsdk:"salt"`
+	Length                 types.Int64   `tfsdk:"length"`
+	MustMatch              types.String  `tfsdk:"must_match"`
+	MustNotMatch           types.String  `tfsdk:"must_not_match"`
+	MaxAttempts            types.Int64   `tfsdk:"max_attempts"`
+	FirstCharAlphabet      types.String  `tfsdk:"first_char_alphabet"`
+	LastCharAlphabet       types.String  `tfsdk:"last_char_alphabet"`
+	NoRepeatRun            types.Bool    `tfsdk:"no_repeat_run"`
+	EntropyBits            types.Float64 `tfsdk:"entropy_bits"`
+	MinEntropyBits         types.Float64 `tfsdk:"min_entropy_bits"`
+	MinLength              types.Int64   `tfsdk:"min_length"`
+	MaxLength              types.Int64   `tfsdk:"max_length"`
+	ExcludeSimilar         types.Bool    `tfsdk:"exclude_similar"`
+	ExcludeCharacters      types.String  `tfsdk:"exclude_characters"`
+	EffectiveAlphabet      types.String  `tfsdk:"effective_alphabet"`
+	CaseInsensitive        types.Bool    `tfsdk:"case_insensitive"`
+	IdUpper                types.String  `tfsdk:"id_upper"`
+	IdLower                types.String  `tfsdk:"id_lower"`
+	IdBase64url            types.String  `tfsdk:"id_base64url"`
+	IdHex                  types.String  `tfsdk:"id_hex"`
+	GroupSize              types.Int64   `tfsdk:"group_size"`
+	GroupSeparator         types.String  `tfsdk:"group_separator"`
+	Format                 types.String  `tfsdk:"format"`
+	Prefix                 types.String  `tfsdk:"prefix"`
+	Suffix                 types.String  `tfsdk:"suffix"`
+	Formatted              types.String  `tfsdk:"formatted"`
+	CreatedAt              types.String  `tfsdk:"created_at"`
+	Algorithm              types.String  `tfsdk:"algorithm"`
+	Generation             types.Int64   `tfsdk:"generation"`
+	RegenerateInPlace      types.Bool    `tfsdk:"regenerate_in_place"`
+	HistorySize            types.Int64   `tfsdk:"history_size"`
+	GenerateAtPlan         types.Bool    `tfsdk:"generate_at_plan"`
+	PreviousIds            types.List    `tfsdk:"previous_ids"`
 }
 
 func (d *IdResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
@@ -50,14 +138,19 @@ func (d *IdResource) Metadata(ctx context.Context, req resource.MetadataRequest,
 
 func (d *IdResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
 	resp.Schema = schema.Schema{
+		Version: KEEPERS_SCHEMA_VERSION,
 		MarkdownDescription: "The id resource generates random strings that are intended to be used as unique identifiers for other resources.\n\n" +
 			"This resource can be used in conjunction with resources that have the `create_before_destroy` lifecycle flag set to avoid conflicts with " +
-			"unique names during the brief period where both the old and new resources exist concurrently.",
+			"unique names during the brief period where both the old and new resources exist concurrently.\n\n" +
+			"Changing `keepers` replaces the resource, unless `regenerate_in_place` is set or `history_size` is above 0: the id is then regenerated in place, " +
+			"with `generation` counting the ids the resource has held and `previous_ids` keeping the regenerated ids.",
 		Attributes: map[string]schema.Attribute{
 			"alphabet": schema.StringAttribute{
 				MarkdownDescription: fmt.Sprintf("Supply your own list of characters to use for id generation.\n"+
-					"Should be between 1 and 255 characters long.\n"+
-					"The default value is `\"%q\"`.", DEFAULT_ID_ALPHABET),
+					"Should be between 1 and %d characters long, each Unicode code point counting as one character.\n"+
+					"Should not contain duplicate, whitespace, control or combining characters, and should be in Unicode normalization form C. "+
+					"Characters of other scripts that look like Latin characters or digits cause a warning.\n"+
+					"The default value is `\"%q\"`.", MAX_ID_ALPHABET_LENGTH, DEFAULT_ID_ALPHABET),
 				Optional: true,
 				Computed: true,
 				Default:  stringdefault.StaticString(DEFAULT_ID_ALPHABET),
@@ -66,31 +159,401 @@ func (d *IdResource) Schema(ctx context.Context, req resource.SchemaRequest, res
 					stringplanmodifier.UseStateForUnknown(),
 				},
 				Validators: []validator.String{
-					stringvalidator.LengthBetween(1, 255),
+					stringvalidator.UTF8LengthBetween(1, MAX_ID_ALPHABET_LENGTH),
+					validAlphabet(),
 				},
 			},
 
 			"length": schema.Int64Attribute{
-				MarkdownDescription: fmt.Sprintf("The length of the desired nanoid.\nShould be between 1 and 64.\nThe default value is %d.", DEFAULT_ID_LENGTH),
-				Optional:            true,
-				Computed:            true,
-				Default:             int64default.StaticInt64(DEFAULT_ID_LENGTH),
+				MarkdownDescription: fmt.Sprintf("The length of the desired nanoid.\n"+
+					"Should be between 1 and the `max_id_length` of the provider, %d by default.\n"+
+					"The default value is %d, or the length drawn between `min_length` and `max_length` when they are set.",
+					DEFAULT_MAX_ID_LENGTH, DEFAULT_ID_LENGTH),
+				Optional: true,
+				Computed: true,
 				PlanModifiers: []planmodifier.Int64{
+					idLengthDefault{},
 					int64planmodifier.RequiresReplace(),
 					int64planmodifier.UseStateForUnknown(),
 				},
 				Validators: []validator.Int64{
-					int64validator.Between(1, 64),
+					int64validator.AtLeast(1),
+					int64validator.ConflictsWith(path.MatchRoot("min_length"), path.MatchRoot("max_length")),
+				},
+			},
+
+			"min_length": schema.Int64Attribute{
+				MarkdownDescription: "The minimum length of the nanoid, when its length is drawn uniformly between `min_length` and `max_length`.\n" +
+					"Conflicts with `length`, which records the drawn length.",
+				Optional: true,
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.RequiresReplace(),
+				},
+				Validators: []validator.Int64{
+					int64validator.AtLeast(1),
+					int64validator.AlsoRequires(path.MatchRoot("max_length")),
+				},
+			},
+
+			"max_length": schema.Int64Attribute{
+				MarkdownDescription: "The maximum length of the nanoid, when its length is drawn uniformly between `min_length` and `max_length`.\n" +
+					"Should be at least `min_length` and at most the `max_id_length` of the provider.",
+				Optional: true,
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.RequiresReplace(),
+				},
+				Validators: []validator.Int64{
+					int64validator.AtLeast(1),
+					int64validator.AlsoRequires(path.MatchRoot("min_length")),
+				},
+			},
+
+			"exclude_similar": schema.BoolAttribute{
+				MarkdownDescription: "Remove the characters that are easily confused with one another, `" + SIMILAR_CHARACTERS + "`, " +
+					"from `alphabet`, `first_char_alphabet` and `last_char_alphabet`, for ids that humans retype from screens.\n" +
+					"The default value is `false`.",
+				Optional: true,
+				PlanModifiers: []planmodifier.Bool{
+					boolplanmodifier.RequiresReplace(),
+				},
+			},
+
+			"exclude_characters": schema.StringAttribute{
+				MarkdownDescription: "Characters to remove from `alphabet`, `first_char_alphabet` and `last_char_alphabet`, " +
+					"in addition to those removed by `exclude_similar`.",
+				Optional: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+
+			"effective_alphabet": schema.StringAttribute{
+				MarkdownDescription: "The alphabet the id is generated with, after removing the excluded characters. " +
+					"Check characters are computed over this alphabet.",
+				Computed: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+
+			"first_char_alphabet": schema.StringAttribute{
+				MarkdownDescription: "The characters to use for the first character of the id, instead of `alphabet`, " +
+					"for example letters only for systems requiring a leading letter.\n" +
+					"Should be between 1 and " + strconv.Itoa(MAX_ID_ALPHABET_LENGTH) + " characters long, with the same checks as `alphabet`.\n" +
+					"With `checksum`, should only contain characters of `alphabet`, over which the check character is computed.",
+				Optional: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+				Validators: []validator.String{
+					stringvalidator.UTF8LengthBetween(1, MAX_ID_ALPHABET_LENGTH),
+					validAlphabet(),
+				},
+			},
+
+			"last_char_alphabet": schema.StringAttribute{
+				MarkdownDescription: "The characters to use for the last character of the id, instead of `alphabet`.\n" +
+					"Should be between 1 and " + strconv.Itoa(MAX_ID_ALPHABET_LENGTH) + " characters long, with the same checks as `alphabet`.\n" +
+					"Conflicts with `checksum`, whose check character is always last.",
+				Optional: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+				Validators: []validator.String{
+					stringvalidator.UTF8LengthBetween(1, MAX_ID_ALPHABET_LENGTH),
+					validAlphabet(),
+					stringvalidator.ConflictsWith(path.MatchRoot("checksum")),
+				},
+			},
+
+			"no_repeat_run": schema.BoolAttribute{
+				MarkdownDescription: "Never generate the same character twice in a row.\n" +
+					"Each character is sampled from the characters of its alphabet that differ from the previous character, " +
+					"so ids are never rejected and the distribution stays uniform among the allowed ids.\n" +
+					"The default value is `false`.",
+				Optional: true,
+				PlanModifiers: []planmodifier.Bool{
+					boolplanmodifier.RequiresReplace(),
+				},
+			},
+
+			"case_insensitive": schema.BoolAttribute{
+				MarkdownDescription: "Treat the upper and lower case of a character as one symbol, for ids used in case-insensitive systems " +
+					"such as DNS names or Windows paths.\n" +
+					"The characters of an alphabet that only differ by case then count as one symbol, more likely than the others, " +
+					"in `entropy_bits` and `min_entropy_bits`, and `no_repeat_run` never generates the same symbol twice in a row.\n" +
+					"The default value is `false`.",
+				Optional: true,
+				PlanModifiers: []planmodifier.Bool{
+					boolplanmodifier.RequiresReplace(),
+				},
+			},
+
+			"checksum": schema.StringAttribute{
+				MarkdownDescription: "Append a check character, computed over the alphabet, to the generated nanoid.\n" +
+					"Should be one of `luhn` (Luhn mod N), `damm`, `verhoeff` or `iso7064` (ISO/IEC 7064 hybrid system MOD N+1,N).\n" +
+					"The `damm` algorithm requires an alphabet size of 10 or not congruent to 2 modulo 4, " +
+					"and the `verhoeff` algorithm requires an alphabet of 10 characters.\n" +
+					"The `luhn` algorithm only detects every single character substitution with alphabets of even size.\n" +
+					"The alphabet must not contain duplicate characters.",
+				Optional: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+				Validators: []validator.String{
+					stringvalidator.OneOf(CHECKSUM_ALGORITHMS...),
+				},
+			},
+
+			"must_match": schema.StringAttribute{
+				MarkdownDescription: "An [RE2 regular expression](https://github.com/google/re2/wiki/Syntax) the generated id must match, " +
+					"for example `[0-9].*[0-9]` for ids containing at least two digits.\n" +
+					"Ids are generated again until they satisfy `must_match` and `must_not_match`, up to `max_attempts` times. " +
+					"The constraints apply to the id including its check character.",
+				Optional: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+				Validators: []validator.String{
+					isRegex(),
+				},
+			},
+
+			"must_not_match": schema.StringAttribute{
+				MarkdownDescription: "An [RE2 regular expression](https://github.com/google/re2/wiki/Syntax) the generated id must not match, " +
+					"for example `^[0-9]` for ids not starting with a digit.",
+				Optional: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+				Validators: []validator.String{
+					isRegex(),
+				},
+			},
+
+			"max_attempts": schema.Int64Attribute{
+				MarkdownDescription: fmt.Sprintf("The maximum number of ids to generate to satisfy `must_match` and `must_not_match`.\n"+
+					"Should be between 1 and %d.\n"+
+					"The default value is %d.\n"+
+					"The probability that an id satisfies the constraints is estimated from %d ids sampled with a fixed seed before applying, "+
+					"and configurations that would succeed within `max_attempts` with a probability below %g%% are rejected.",
+					MAX_ID_MAX_ATTEMPTS, DEFAULT_ID_MAX_ATTEMPTS, ID_CONSTRAINT_SAMPLES, ID_CONSTRAINT_MIN_SUCCESS*100),
+				Optional: true,
+				Computed: true,
+				Default:  int64default.StaticInt64(DEFAULT_ID_MAX_ATTEMPTS),
+				Validators: []validator.Int64{
+					int64validator.Between(1, MAX_ID_MAX_ATTEMPTS),
+				},
+			},
+
+			"group_size": schema.Int64Attribute{
+				MarkdownDescription: "The number of characters of the groups the id is split into in `formatted`, the last group possibly being shorter.\n" +
+					"Changes are applied in place, as `id` stays the raw value.",
+				Optional: true,
+				Validators: []validator.Int64{
+					int64validator.AtLeast(1),
+				},
+			},
+
+			"group_separator": schema.StringAttribute{
+				MarkdownDescription: "The string placed between the groups of the id in `formatted`.\n" +
+					"Must not contain characters of the alphabets, so that the formatted id can be parsed back.\n" +
+					"The default value is `\"" + DEFAULT_ID_GROUP_SEPARATOR + "\"`, which is not a character of the default alphabet.",
+				Optional: true,
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+					stringvalidator.AlsoRequires(path.MatchRoot("group_size")),
+				},
+			},
+
+			"format": schema.StringAttribute{
+				MarkdownDescription: "A template rendered into `formatted`, such as `{prefix}-{id}-{suffix}`.\n" +
+					"Should contain the `{id}` placeholder once, replaced with the grouped id, " +
+					"and may contain the `{prefix}` and `{suffix}` placeholders, replaced with `prefix` and `suffix`.\n" +
+					"Changes are applied in place.",
+				Optional: true,
+				Validators: []validator.String{
+					isFormat(ID_FORMAT_PLACEHOLDERS),
 				},
 			},
 
-			"keepers": schema.MapAttribute{
-				Description: "Arbitrary map of values that, when changed, will trigger recreation of " +
-					"resource. See [the main provider documentation](../index.html) for more information.",
+			"prefix": schema.StringAttribute{
+				MarkdownDescription: "The value of the `{prefix}` placeholder of `format`.",
+				Optional:            true,
+				Validators: []validator.String{
+					stringvalidator.AlsoRequires(path.MatchRoot("format")),
+				},
+			},
+
+			"suffix": schema.StringAttribute{
+				MarkdownDescription: "The value of the `{suffix}` placeholder of `format`.",
+				Optional:            true,
+				Validators: []validator.String{
+					stringvalidator.AlsoRequires(path.MatchRoot("format")),
+				},
+			},
+
+			"formatted": schema.StringAttribute{
+				MarkdownDescription: "The id split into groups and rendered with `format`, or the id when neither is set.",
+				Computed:            true,
+			},
+
+			"keepers": schema.DynamicAttribute{
+				Description: "Arbitrary values of any type that, when changed, will trigger recreation of resource, " +
+					"or regenerate the id in place and increment `generation` when `regenerate_in_place` is set or `history_size` is above 0. " +
+					"See [the main provider documentation](../index.html) for more information.",
+				Optional: true,
+			},
+
+			"keepers_sensitive": schema.DynamicAttribute{
+				MarkdownDescription: "Arbitrary sensitive values, such as credential versions, that, when changed, will trigger recreation of resource, " +
+					"or regenerate the id in place and increment `generation` when `regenerate_in_place` is set or `history_size` is above 0. " +
+					"The values are write-only: only their salted digest is stored, in `keepers_sensitive_digest`. Write-only attributes require Terraform 1.11 or later.",
+				Optional:  true,
+				Sensitive: true,
+				WriteOnly: true,
+			},
+
+			"regenerate_in_place": schema.BoolAttribute{
+				MarkdownDescription: "Regenerate the id in place when `keepers` or `keepers_sensitive` change, incrementing `generation`, " +
+					"instead of replacing the resource.\n" +
+					"Implied by a `history_size` above 0, as a replacement would lose the previous ids. Changes are applied in place.\n" +
+					"The default value is `false`.",
+				Optional: true,
+			},
+
+			"keepers_sensitive_digest": schema.StringAttribute{
+				MarkdownDescription: "The salted SHA-256 digest of `keepers_sensitive`, of the form `sha256:<salt>:<digest>`, " +
+					"against which their configured values are compared.",
+				Computed: true,
+				PlanModifiers: []planmodifier.String{
+					keepersSensitiveDigestModifier{requiresReplace: false},
+				},
+			},
+
+			"labels": schema.MapAttribute{
+				MarkdownDescription: "Arbitrary map of metadata, such as an owner, a ticket or a purpose. " +
+					"Unlike `keepers`, changes are applied in place and never regenerate the id.",
 				ElementType: types.StringType,
 				Optional:    true,
-				PlanModifiers: []planmodifier.Map{
-					mapplanmodifier.RequiresReplaceIfConfigured(),
+			},
+
+			"seed": schema.StringAttribute{
+				MarkdownDescription: "A secret from which the id is derived deterministically with HMAC-DRBG, over the seed, `salt`, `keepers`, `keepers_sensitive` and `generation`, " +
+					"instead of being generated randomly. Re-applying with the same seed, salt, keepers and sensitive keepers, for example after the state is lost, " +
+					"reproduces the same id of the first generation. Ids regenerated in place, see `regenerate_in_place`, also derive from their generation, " +
+					"so that changing `keepers` back to earlier values derives a new id rather than a retired one. " +
+					"To recover such an id after the state is lost, also set `generation` to the generation it had, " +
+					"then remove `generation` once it is applied. " +
+					"The seed is write-only and never stored, so setting or changing it alone does not regenerate the id. " +
+					"Write-only attributes require Terraform 1.11 or later.",
+				Optional:  true,
+				Sensitive: true,
+				WriteOnly: true,
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+				},
+			},
+
+			"salt": schema.StringAttribute{
+				MarkdownDescription: "A value mixed into the derivation of the id from `seed`, to derive distinct ids from the same seed. " +
+					"Requires `seed`. Changes force a new id.",
+				Optional: true,
+				Validators: []validator.String{
+					stringvalidator.AlsoRequires(path.MatchRoot("seed")),
+				},
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+
+			"min_entropy_bits": schema.Float64Attribute{
+				MarkdownDescription: "The minimum entropy of the generated id in bits, checked against the combination of the alphabets and `length` " +
+					"when the configuration is validated, so that weak ids are caught before they are generated.",
+				Optional: true,
+				Validators: []validator.Float64{
+					float64validator.AtLeast(0),
+				},
+			},
+
+			"entropy_bits": schema.Float64Attribute{
+				MarkdownDescription: "The entropy of the generated id in bits, given its alphabets and length, " +
+					"before `must_match` and `must_not_match` are applied. A check character adds no entropy.\n" +
+					"With `case_insensitive`, the entropy of the id read without its case.",
+				Computed: true,
+				PlanModifiers: []planmodifier.Float64{
+					float64planmodifier.UseStateForUnknown(),
+				},
+			},
+
+			"history_size": schema.Int64Attribute{
+				MarkdownDescription: fmt.Sprintf("The number of previous ids to keep in `previous_ids` when changing `keepers` regenerates the id, "+
+					"so that the old and new ids can be used together during a cutover. "+
+					"Above 0, changing `keepers` regenerates the id in place, as with `regenerate_in_place`, instead of replacing the resource, "+
+					"since Terraform plans a replacing resource without the state of the replaced one.\n"+
+					"Should be between 0 and %d. Changes are applied in place.\n"+
+					"The default value is 0.", MAX_ID_HISTORY_SIZE),
+				Optional: true,
+				Validators: []validator.Int64{
+					int64validator.Between(0, MAX_ID_HISTORY_SIZE),
+				},
+			},
+
+			"generate_at_plan": schema.BoolAttribute{
+				MarkdownDescription: "Plan the id instead of leaving it unknown until after apply.\n" +
+					"When `seed` is set, the id is derived in the plan, including for new resources. " +
+					"Otherwise, the next id is drawn in advance and kept in the private state of the resource, " +
+					"so only the ids regenerated in place by changing `keepers`, see `regenerate_in_place` and `history_size`, are known in the plan: " +
+					"new and replacing resources without `seed` are planned with an unknown id, and a warning, as Terraform plans them again when applying, " +
+					"without any state to carry an id drawn in advance. Without `seed`, requires `regenerate_in_place` or `history_size`. " +
+					"Changes are applied in place.\n" +
+					"The default value is `false`.",
+				Optional: true,
+			},
+
+			"previous_ids": schema.ListAttribute{
+				MarkdownDescription: "The ids regenerated in place because `keepers` changed, the most recent first, up to `history_size` of them. " +
+					"Replacements caused by other attributes start again without previous ids.",
+				ElementType: types.StringType,
+				Computed:    true,
+			},
+
+			"created_at": schema.StringAttribute{
+				MarkdownDescription: "The time the id was generated, in RFC 3339 format.\n" +
+					"Null for imported ids and ids generated by earlier versions of the provider.",
+				Computed: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+
+			"algorithm": schema.StringAttribute{
+				MarkdownDescription: "The algorithm that generated the id, `" + ID_ALGORITHM + "` for ids sampled with the nanoid engine from a " +
+					"cryptographically secure random source, or `" + SEEDED_ID_ALGORITHM + "` for ids derived from `seed`.\n" +
+					"Null for imported ids and ids generated by earlier versions of the provider.",
+				Computed: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+
+			"generation": schema.Int64Attribute{
+				MarkdownDescription: "The number of ids the resource has held: 1 for its first id, incremented every time changing `keepers` regenerates the id in place, " +
+					"see `regenerate_in_place` and `history_size`. " +
+					"It does not count replacements: a replacing resource, including one caused by `keepers` without `regenerate_in_place`, " +
+					"is planned without the state of the replaced one and starts again at 1, so set `regenerate_in_place` to count every id forced by `keepers`.\n" +
+					"Ids imported or generated by earlier versions of the provider have no generation, and the ids regenerated for them start at 1.\n" +
+					"Can be set to the generation of an id derived from `seed` to derive it again, for example to recover an id regenerated in place after the state is lost. " +
+					"While set, changing it regenerates the id like changing `keepers`, and changing `keepers` keeps it, " +
+					"so removing it once the id is recovered lets the next regenerations count again.\n" +
+					"Should be at least 1.",
+				Optional: true,
+				Computed: true,
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.UseStateForUnknown(),
+				},
+				Validators: []validator.Int64{
+					int64validator.AtLeast(1),
 				},
 			},
 
@@ -101,6 +564,38 @@ func (d *IdResource) Schema(ctx context.Context, req resource.SchemaRequest, res
 					stringplanmodifier.UseStateForUnknown(),
 				},
 			},
+
+			"id_upper": schema.StringAttribute{
+				MarkdownDescription: "The id in upper case.",
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+
+			"id_lower": schema.StringAttribute{
+				MarkdownDescription: "The id in lower case.",
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+
+			"id_base64url": schema.StringAttribute{
+				MarkdownDescription: "The UTF-8 bytes of the id, encoded with the URL and filename safe base64 alphabet of RFC 4648, without padding.",
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+
+			"id_hex": schema.StringAttribute{
+				MarkdownDescription: "The UTF-8 bytes of the id, encoded in lower case hexadecimal.",
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
 		},
 	}
 }
@@ -111,7 +606,7 @@ func (d *IdResource) Configure(ctx context.Context, req resource.ConfigureReques
 		return
 	}
 
-	_, ok := req.ProviderData.(*NanoidProviderData)
+	providerData, ok := req.ProviderData.(*NanoidProviderData)
 	if !ok {
 		resp.Diagnostics.AddError(
 			"Unexpected Data Source Configure Type",
@@ -120,34 +615,164 @@ func (d *IdResource) Configure(ctx context.Context, req resource.ConfigureReques
 
 		return
 	}
+
+	d.providerData = providerData
 }
 
-func (r *IdResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+// maxIdLength returns the maximum length of the ids, configured on the provider.
+func (r *IdResource) maxIdLength() int64 {
+	if r.providerData == nil {
+		return DEFAULT_MAX_ID_LENGTH
+	}
+
+	return r.providerData.maxIdLength
+}
+
+func (r *IdResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+
 	var data IdResourceModel
 	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	alphabet := data.Alphabet.ValueString()
-	if data.Alphabet.IsNull() {
-		alphabet = DEFAULT_ID_ALPHABET
+	// A replacement would lose the previous ids.
+	regenerate := planKeepersRegeneration(ctx, req, resp, data.HistorySize.ValueInt64() > 0)
+	if resp.Diagnostics.HasError() {
+		return
 	}
 
-	length := data.Length.ValueInt64()
-	if data.Length.IsNull() {
-		length = DEFAULT_ID_LENGTH
+	resp.Diagnostics.Append(planPreviousIds(ctx, req, resp, data.HistorySize, regenerate)...)
+	if resp.Diagnostics.HasError() {
+		return
 	}
 
-	id, err := gonanoid.Generate(alphabet, int(length))
-	if err != nil {
-		resp.Diagnostics.AddError("Failed to generate id", fmt.Sprintf("Failed to generate id: %s.", err))
+	// The id of an existing resource is known, so the changes to its
+	// formatted form are planned.
+	if !req.State.Raw.IsNull() && !regenerate {
+		var plan IdResourceModel
+		resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+
+		plan.setIdForms()
+		resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("formatted"), plan.Formatted)...)
 		return
 	}
 
-	data.Id = types.StringValue(id)
-	data.Alphabet = types.StringValue(alphabet)
-	data.Length = types.Int64Value(length)
+	// Only ids that are about to be generated are checked, so that lowering
+	// max_id_length does not affect existing ids.
+	maxIdLength := r.maxIdLength()
+	for name, length := range map[string]types.Int64{"length": data.Length, "max_length": data.MaxLength} {
+		if !length.IsNull() && !length.IsUnknown() && length.ValueInt64() > maxIdLength {
+			resp.Diagnostics.AddAttributeError(path.Root(name), "Invalid Attribute Value",
+				fmt.Sprintf("Attribute %s must be at most %d, the max_id_length of the provider, got: %d", name, maxIdLength, length.ValueInt64()))
+		}
+	}
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Ids derived from a seed are derived again identically when applying, so
+	// they are planned even for new resources, which have no id drawn in
+	// advance.
+	if (req.State.Raw.IsNull() || regenerate) && data.GenerateAtPlan.ValueBool() && !data.Seed.IsNull() {
+		if !req.Config.Raw.IsFullyKnown() {
+			return
+		}
+
+		var plan IdResourceModel
+		resp.Diagnostics.Append(resp.Plan.Get(ctx, &plan)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		derived := data
+		derived.Generation = plan.Generation
+		resp.Diagnostics.Append(derived.generateId(ctx)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+
+		plan.Id = derived.Id
+		plan.Alphabet = derived.Alphabet
+		plan.Length = derived.Length
+		plan.MaxAttempts = derived.MaxAttempts
+		plan.EntropyBits = derived.EntropyBits
+		plan.EffectiveAlphabet = derived.EffectiveAlphabet
+		plan.Algorithm = derived.Algorithm
+		plan.setIdForms()
+		resp.Diagnostics.Append(resp.Plan.Set(ctx, &plan)...)
+		return
+	}
+
+	if req.State.Raw.IsNull() && data.GenerateAtPlan.ValueBool() && data.Seed.IsNull() {
+		resp.Diagnostics.AddAttributeWarning(path.Root("generate_at_plan"), "Id unknown in the plan",
+			"Without seed, generate_at_plan does not plan the id of a new resource, as Terraform plans it again when applying, "+
+				"without any state to carry an id drawn in advance. The id is known after apply, and the ids regenerated in place later are known in the plan.")
+	}
+
+	if regenerate && data.GenerateAtPlan.ValueBool() {
+		next, diags := req.Private.GetKey(ctx, NEXT_ID_PRIVATE_KEY)
+		resp.Diagnostics.Append(diags...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		if len(next) > 0 {
+			var plan IdResourceModel
+			resp.Diagnostics.Append(resp.Plan.Get(ctx, &plan)...)
+			resp.Diagnostics.Append(plan.useNextId(next)...)
+			resp.Diagnostics.Append(resp.Plan.Set(ctx, &plan)...)
+			return
+		}
+	}
+
+	if regenerate {
+		for _, name := range []string{"id_upper", "id_lower", "id_base64url", "id_hex", "formatted"} {
+			resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root(name), types.StringUnknown())...)
+		}
+		// The length is drawn again.
+		if !data.MinLength.IsNull() {
+			resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("length"), types.Int64Unknown())...)
+			resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("entropy_bits"), types.Float64Unknown())...)
+		}
+	}
+}
+
+func (r *IdResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data IdResourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var plan IdResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// An id derived at plan time is created as planned.
+	if plan.Id.IsUnknown() {
+		data.Generation = plan.Generation
+		resp.Diagnostics.Append(data.generateId(ctx)...)
+	} else {
+		plan.CreatedAt = types.StringValue(time.Now().UTC().Format(time.RFC3339))
+		plan.Seed = data.Seed
+		data = plan
+	}
+	data.PreviousIds = plan.PreviousIds
+	if data.GenerateAtPlan.ValueBool() && data.Seed.IsNull() {
+		resp.Diagnostics.Append(data.drawNextId(ctx, resp.Private)...)
+	}
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(setKeepersSensitiveDigest(ctx, req.Config, &data.KeepersSensitiveDigest)...)
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
 }
 
@@ -158,19 +783,84 @@ func (d *IdResource) Read(ctx context.Context, req resource.ReadRequest, resp *r
 		return
 	}
 
+	// Ids generated by earlier versions of the provider have no other forms
+	// and no previous ids yet.
+	data.setIdForms()
+	if data.PreviousIds.IsNull() {
+		data.PreviousIds = types.ListValueMust(types.StringType, []attr.Value{})
+	}
+
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
 }
 
 func (r *IdResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
 	var data IdResourceModel
-	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	// The seed and the sensitive keepers are write-only, so they are only in
+	// the configuration.
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("seed"), &data.Seed)...)
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("keepers_sensitive"), &data.KeepersSensitive)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var state IdResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	next, diags := req.Private.GetKey(ctx, NEXT_ID_PRIVATE_KEY)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// The id is only regenerated when the keepers changed, honoring the id
+	// planned when it was drawn in advance. Otherwise it is kept from the
+	// state with its creation metadata, so only attributes which do not change
+	// it, such as labels or max_attempts, are applied in place.
+	regenerated := !data.Id.Equal(state.Id)
+	switch {
+	case data.Id.IsUnknown():
+		resp.Diagnostics.Append(data.generateId(ctx)...)
+	case regenerated:
+		data.CreatedAt = types.StringValue(time.Now().UTC().Format(time.RFC3339))
+		data.Algorithm = types.StringValue(ID_ALGORITHM)
+		if !data.Seed.IsNull() {
+			data.Algorithm = types.StringValue(SEEDED_ID_ALGORITHM)
+		}
+		data.setIdForms()
+	default:
+		data.CreatedAt = state.CreatedAt
+		data.Algorithm = state.Algorithm
+		data.Generation = state.Generation
+		data.setIdForms()
+	}
+
+	// Ids derived from a seed are derived at plan time instead.
+	switch {
+	case !data.GenerateAtPlan.ValueBool() || !data.Seed.IsNull():
+		resp.Diagnostics.Append(resp.Private.SetKey(ctx, NEXT_ID_PRIVATE_KEY, nil)...)
+	case regenerated || len(next) == 0:
+		resp.Diagnostics.Append(data.drawNextId(ctx, resp.Private)...)
+	}
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
+	resp.Diagnostics.Append(setKeepersSensitiveDigest(ctx, req.Config, &data.KeepersSensitiveDigest)...)
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
 }
 
+func (r *IdResource) ConfigValidators(ctx context.Context) []resource.ConfigValidator {
+	return []resource.ConfigValidator{
+		minEntropyBits(func() entropyModel { return &IdResourceModel{} }),
+	}
+}
+
+func (r *IdResource) UpgradeState(ctx context.Context) map[int64]resource.StateUpgrader {
+	return map[int64]resource.StateUpgrader{
+		0: keepersStateUpgrader(),
+	}
+}
+
 func (r *IdResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
 	var data IdResourceModel
 	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
@@ -181,18 +871,51 @@ func (r *IdResource) Delete(ctx context.Context, req resource.DeleteRequest, res
 
 func (r *IdResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
 	id := req.ID
-	length := len(id)
-	if length > 64 {
-		resp.Diagnostics.AddError("Invalid id", "The id must be at most 64 characters long.")
+	length := utf8.RuneCountInString(id)
+	if maxIdLength := r.maxIdLength(); int64(length) > maxIdLength {
+		resp.Diagnostics.AddError("Invalid id", fmt.Sprintf("The id must be at most %d characters long.", maxIdLength))
 		return
 	}
 
 	state := &IdResourceModel{
-		Id:       types.StringValue(id),
-		Length:   types.Int64Value(int64(length)),
-		Keepers:  types.MapNull(types.StringType),
-		Alphabet: types.StringValue(DEFAULT_ID_ALPHABET),
+		Id:                     types.StringValue(id),
+		Length:                 types.Int64Value(int64(length)),
+		Keepers:                types.DynamicNull(),
+		KeepersSensitive:       types.DynamicNull(),
+		KeepersSensitiveDigest: types.StringNull(),
+		Labels:                 types.MapNull(types.StringType),
+		Seed:                   types.StringNull(),
+		Salt:                   types.StringNull(),
+		Alphabet:               types.StringValue(DEFAULT_ID_ALPHABET),
+		Checksum:               types.StringNull(),
+		MustMatch:              types.StringNull(),
+		MustNotMatch:           types.StringNull(),
+		MaxAttempts:            types.Int64Value(DEFAULT_ID_MAX_ATTEMPTS),
+		FirstCharAlphabet:      types.StringNull(),
+		LastCharAlphabet:       types.StringNull(),
+		NoRepeatRun:            types.BoolNull(),
+		MinEntropyBits:         types.Float64Null(),
+		MinLength:              types.Int64Null(),
+		MaxLength:              types.Int64Null(),
+		ExcludeSimilar:         types.BoolNull(),
+		ExcludeCharacters:      types.StringNull(),
+		EffectiveAlphabet:      types.StringValue(DEFAULT_ID_ALPHABET),
+		CaseInsensitive:        types.BoolNull(),
+		GroupSize:              types.Int64Null(),
+		GroupSeparator:         types.StringNull(),
+		Format:                 types.StringNull(),
+		Prefix:                 types.StringNull(),
+		Suffix:                 types.StringNull(),
+		CreatedAt:              types.StringNull(),
+		Algorithm:              types.StringNull(),
+		Generation:             types.Int64Null(),
+		RegenerateInPlace:      types.BoolNull(),
+		HistorySize:            types.Int64Null(),
+		GenerateAtPlan:         types.BoolNull(),
+		PreviousIds:            types.ListValueMust(types.StringType, []attr.Value{}),
 	}
+	state.setIdForms()
+	state.EntropyBits = types.Float64Value(state.positions().entropyBits(length))
 
 	diags := resp.State.Set(ctx, &state)
 	resp.Diagnostics.Append(diags...)
@@ -200,3 +923,457 @@ func (r *IdResource) ImportState(ctx context.Context, req resource.ImportStateRe
 		return
 	}
 }
+
+func (r *IdResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var data IdResourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Without a seed, only the ids regenerated in place can be drawn in
+	// advance, see ModifyPlan.
+	if data.GenerateAtPlan.ValueBool() && data.Seed.IsNull() && !data.RegenerateInPlace.ValueBool() &&
+		!data.RegenerateInPlace.IsUnknown() && !data.HistorySize.IsUnknown() && data.HistorySize.ValueInt64() == 0 {
+		resp.Diagnostics.AddAttributeError(path.Root("generate_at_plan"), "Id unknown in the plan",
+			"Without seed, generate_at_plan only plans the ids regenerated in place when keepers change, see regenerate_in_place and history_size, "+
+				"as Terraform plans new and replacing resources again when applying, without any state to carry an id drawn in advance. "+
+				"Set seed, regenerate_in_place or history_size.")
+	}
+
+	if data.Alphabet.IsUnknown() || data.FirstCharAlphabet.IsUnknown() || data.LastCharAlphabet.IsUnknown() ||
+		data.ExcludeSimilar.IsUnknown() || data.ExcludeCharacters.IsUnknown() {
+		return
+	}
+
+	if !data.MinLength.IsNull() && !data.MinLength.IsUnknown() && !data.MaxLength.IsNull() && !data.MaxLength.IsUnknown() &&
+		data.MinLength.ValueInt64() > data.MaxLength.ValueInt64() {
+		resp.Diagnostics.AddAttributeError(path.Root("max_length"), "Invalid Attribute Value",
+			fmt.Sprintf("Attribute max_length must be at least min_length, %d, got: %d", data.MinLength.ValueInt64(), data.MaxLength.ValueInt64()))
+	}
+
+	positions := data.positions()
+	names := []string{"alphabet", "first_char_alphabet", "last_char_alphabet"}
+	for i, alphabet := range [][]rune{positions.alphabet, positions.first, positions.last} {
+		if alphabet != nil && len(alphabet) == 0 {
+			resp.Diagnostics.AddAttributeError(path.Root(names[i]), "Empty alphabet",
+				fmt.Sprintf("Every character of %s is excluded by exclude_similar or exclude_characters.", names[i]))
+		}
+	}
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !data.GroupSize.IsNull() && !data.GroupSeparator.IsUnknown() {
+		separator := data.GroupSeparator.ValueString()
+		if data.GroupSeparator.IsNull() {
+			separator = DEFAULT_ID_GROUP_SEPARATOR
+		}
+		for i, alphabet := range [][]rune{positions.alphabet, positions.first, positions.last} {
+			if strings.ContainsAny(separator, string(alphabet)) {
+				resp.Diagnostics.AddAttributeError(path.Root("group_separator"), "Invalid separator",
+					fmt.Sprintf("The separator %q must not contain characters of %s, set group_separator or exclude the characters with exclude_characters.",
+						separator, names[i]))
+			}
+		}
+	}
+
+	if !data.Checksum.IsNull() && !data.Checksum.IsUnknown() {
+		if err := validateChecksumAlphabet(data.Checksum.ValueString(), string(positions.alphabet)); err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("checksum"), "Unsupported alphabet",
+				fmt.Sprintf("The %s checksum cannot be computed over the alphabet: %s.", data.Checksum.ValueString(), err))
+			return
+		}
+
+		// The check character is computed over the whole id, first character
+		// included.
+		for _, r := range positions.first {
+			if !slices.Contains(positions.alphabet, r) {
+				resp.Diagnostics.AddAttributeError(path.Root("first_char_alphabet"), "Invalid first character alphabet",
+					fmt.Sprintf("The character %q of first_char_alphabet is not part of the alphabet, over which checksum is computed.", r))
+				return
+			}
+		}
+	}
+
+	if data.MustMatch.IsNull() && data.MustNotMatch.IsNull() {
+		return
+	}
+	if data.Length.IsUnknown() || data.MinLength.IsUnknown() || data.MaxLength.IsUnknown() || data.Checksum.IsUnknown() ||
+		data.NoRepeatRun.IsUnknown() || data.CaseInsensitive.IsUnknown() ||
+		data.MustMatch.IsUnknown() || data.MustNotMatch.IsUnknown() || data.MaxAttempts.IsUnknown() {
+		return
+	}
+
+	// Invalid values are reported by the attribute validators.
+	generator, err := newIdGenerator(&data)
+	if err != nil {
+		return
+	}
+	accepted, err := generator.estimateAcceptance(ID_CONSTRAINT_SAMPLES)
+	if err != nil {
+		return
+	}
+
+	maxAttempts := data.MaxAttempts.ValueInt64()
+	if data.MaxAttempts.IsNull() {
+		maxAttempts = DEFAULT_ID_MAX_ATTEMPTS
+	}
+
+	if accepted == 0 {
+		resp.Diagnostics.AddAttributeError(path.Root("must_match"), "Unsatisfiable constraints",
+			fmt.Sprintf("None of %d sampled ids satisfied must_match and must_not_match, so an id would practically never be generated.", ID_CONSTRAINT_SAMPLES))
+		return
+	}
+
+	success := 1 - math.Pow(1-accepted, float64(maxAttempts))
+	if success < ID_CONSTRAINT_MIN_SUCCESS {
+		needed := math.Ceil(math.Log(1-ID_CONSTRAINT_MIN_SUCCESS) / math.Log(1-accepted))
+		resp.Diagnostics.AddAttributeError(path.Root("max_attempts"), "Unlikely constraints",
+			fmt.Sprintf("An estimated %.4g%% of the ids satisfy must_match and must_not_match, so an id would be generated within %d attempts "+
+				"with a probability of %.4g%%. Relax the constraints or set max_attempts to at least %.0f.", accepted*100, maxAttempts, success*100, needed))
+	}
+}
+
+// idGenerator generates the candidate ids of a nanoid_id resource and checks
+// them against its constraints.
+type idGenerator struct {
+	alphabet     string
+	minLength    int
+	maxLength    int
+	positions    *positionalAlphabets
+	checksum     string
+	mustMatch    *regexp.Regexp
+	mustNotMatch *regexp.Regexp
+}
+
+func newIdGenerator(data *IdResourceModel) (*idGenerator, error) {
+	g := &idGenerator{
+		alphabet: data.Alphabet.ValueString(),
+		checksum: data.Checksum.ValueString(),
+	}
+	if data.Alphabet.IsNull() {
+		g.alphabet = DEFAULT_ID_ALPHABET
+	}
+	g.minLength, g.maxLength = data.lengthRange()
+	g.positions = data.positions()
+
+	var err error
+	if !data.MustMatch.IsNull() {
+		if g.mustMatch, err = regexp.Compile(data.MustMatch.ValueString()); err != nil {
+			return nil, err
+		}
+	}
+	if !data.MustNotMatch.IsNull() {
+		if g.mustNotMatch, err = regexp.Compile(data.MustNotMatch.ValueString()); err != nil {
+			return nil, err
+		}
+	}
+
+	return g, nil
+}
+
+// generate returns a candidate id, including its check character, and its
+// length drawn uniformly between the minimum and maximum lengths.
+func (g *idGenerator) generate() (string, int, error) {
+	length := g.minLength
+	if g.maxLength > g.minLength {
+		n, err := randomInt(g.positions.random, g.maxLength-g.minLength+1)
+		if err != nil {
+			return "", 0, err
+		}
+		length += n
+	}
+
+	id, err := g.positions.generate(length)
+	if err != nil {
+		return "", 0, err
+	}
+
+	if g.checksum != "" {
+		check, err := computeChecksum(g.checksum, string(g.positions.alphabet), id)
+		if err != nil {
+			return "", 0, fmt.Errorf("failed to compute checksum: %w", err)
+		}
+		id += check
+	}
+
+	return id, length, nil
+}
+
+// accepts reports whether the id satisfies the constraints.
+func (g *idGenerator) accepts(id string) bool {
+	if g.mustMatch != nil && !g.mustMatch.MatchString(id) {
+		return false
+	}
+	if g.mustNotMatch != nil && g.mustNotMatch.MatchString(id) {
+		return false
+	}
+
+	return true
+}
+
+// estimateAcceptance returns the fraction of sampled ids satisfying the
+// constraints. The ids are sampled from ID_CONSTRAINT_SAMPLER_SEED so that
+// the estimate of a configuration is always the same.
+func (g *idGenerator) estimateAcceptance(samples int) (float64, error) {
+	random := g.positions.random
+	defer func() { g.positions.random = random }()
+	g.positions.random = rand.NewChaCha8(sha256.Sum256([]byte(ID_CONSTRAINT_SAMPLER_SEED)))
+
+	accepted := 0
+	for i := 0; i < samples; i++ {
+		id, _, err := g.generate()
+		if err != nil {
+			return 0, err
+		}
+		if g.accepts(id) {
+			accepted++
+		}
+	}
+
+	return float64(accepted) / float64(samples), nil
+}
+
+// positions returns the alphabets of the positions of the id, without the
+// excluded characters.
+func (data *IdResourceModel) positions() *positionalAlphabets {
+	excluded := data.ExcludeCharacters.ValueString()
+	if data.ExcludeSimilar.ValueBool() {
+		excluded += SIMILAR_CHARACTERS
+	}
+
+	alphabet := data.Alphabet.ValueString()
+	if data.Alphabet.IsNull() {
+		alphabet = DEFAULT_ID_ALPHABET
+	}
+	p := &positionalAlphabets{
+		alphabet:        excludeCharacters(alphabet, excluded),
+		noRepeatRun:     data.NoRepeatRun.ValueBool(),
+		caseInsensitive: data.CaseInsensitive.ValueBool(),
+	}
+	if !data.FirstCharAlphabet.IsNull() {
+		p.first = excludeCharacters(data.FirstCharAlphabet.ValueString(), excluded)
+	}
+	if !data.LastCharAlphabet.IsNull() {
+		p.last = excludeCharacters(data.LastCharAlphabet.ValueString(), excluded)
+	}
+
+	return p
+}
+
+// generateId generates an id satisfying the constraints, up to max_attempts
+// times, and records it with its creation metadata. The candidates are
+// derived from the seed when set.
+func (data *IdResourceModel) generateId(ctx context.Context) (diags diag.Diagnostics) {
+	random, diags := seededRandom(ctx, data.Seed, data.Salt, data.Keepers, data.KeepersSensitive, data.Generation)
+	if diags.HasError() {
+		return diags
+	}
+
+	generator, err := newIdGenerator(data)
+	if err != nil {
+		diags.AddError("Failed to generate id", fmt.Sprintf("Failed to generate id: %s.", err))
+		return diags
+	}
+	generator.positions.random = random
+
+	maxAttempts := data.MaxAttempts.ValueInt64()
+	if data.MaxAttempts.IsNull() {
+		maxAttempts = DEFAULT_ID_MAX_ATTEMPTS
+	}
+
+	var id string
+	var length int
+	for attempt := int64(1); ; attempt++ {
+		id, length, err = generator.generate()
+		if err != nil {
+			diags.AddError("Failed to generate id", fmt.Sprintf("Failed to generate id: %s.", err))
+			return diags
+		}
+		if generator.accepts(id) {
+			break
+		}
+		if attempt == maxAttempts {
+			diags.AddError("Failed to generate id",
+				fmt.Sprintf("Failed to generate id: none of the %d generated ids satisfied must_match and must_not_match.", maxAttempts))
+			return diags
+		}
+	}
+
+	data.Id = types.StringValue(id)
+	data.Alphabet = types.StringValue(generator.alphabet)
+	data.Length = types.Int64Value(int64(length))
+	data.MaxAttempts = types.Int64Value(maxAttempts)
+	data.EntropyBits = types.Float64Value(generator.positions.entropyBits(length))
+	data.EffectiveAlphabet = types.StringValue(string(generator.positions.alphabet))
+	data.CreatedAt = types.StringValue(time.Now().UTC().Format(time.RFC3339))
+	data.Algorithm = types.StringValue(ID_ALGORITHM)
+	if random != nil {
+		data.Algorithm = types.StringValue(SEEDED_ID_ALGORITHM)
+	}
+	data.setIdForms()
+
+	return diags
+}
+
+// privateState is the private state of a resource, in the responses of the
+// resource methods.
+type privateState interface {
+	SetKey(ctx context.Context, key string, value []byte) diag.Diagnostics
+}
+
+// nextId is the id drawn in advance for the next regeneration, kept in the
+// private state of resources generating ids at plan time.
+type nextId struct {
+	Id     string `json:"id"`
+	Length int64  `json:"length"`
+}
+
+// drawNextId draws the id of the next regeneration in advance.
+func (data *IdResourceModel) drawNextId(ctx context.Context, private privateState) (diags diag.Diagnostics) {
+	next := *data
+	diags.Append(next.generateId(ctx)...)
+	if diags.HasError() {
+		return diags
+	}
+
+	value, err := json.Marshal(nextId{Id: next.Id.ValueString(), Length: next.Length.ValueInt64()})
+	if err != nil {
+		diags.AddError("Failed to generate id", fmt.Sprintf("Failed to generate id: %s.", err))
+		return diags
+	}
+	diags.Append(private.SetKey(ctx, NEXT_ID_PRIVATE_KEY, value)...)
+
+	return diags
+}
+
+// useNextId plans the id drawn in advance with its forms.
+func (data *IdResourceModel) useNextId(value []byte) (diags diag.Diagnostics) {
+	var next nextId
+	if err := json.Unmarshal(value, &next); err != nil {
+		diags.AddError("Failed to plan id", fmt.Sprintf("Failed to plan id: %s.", err))
+		return diags
+	}
+
+	data.Id = types.StringValue(next.Id)
+	data.Length = types.Int64Value(next.Length)
+	data.EntropyBits = types.Float64Value(data.positions().entropyBits(int(next.Length)))
+	data.setIdForms()
+
+	return diags
+}
+
+// setIdForms computes the other forms of the id, including its formatted form.
+func (data *IdResourceModel) setIdForms() {
+	if data.Id.IsNull() || data.Id.IsUnknown() {
+		return
+	}
+
+	id := data.Id.ValueString()
+	data.IdUpper = types.StringValue(strings.ToUpper(id))
+	data.IdLower = types.StringValue(strings.ToLower(id))
+	data.IdBase64url = types.StringValue(base64.RawURLEncoding.EncodeToString([]byte(id)))
+	data.IdHex = types.StringValue(hex.EncodeToString([]byte(id)))
+
+	if data.GroupSize.IsUnknown() || data.GroupSeparator.IsUnknown() || data.Format.IsUnknown() ||
+		data.Prefix.IsUnknown() || data.Suffix.IsUnknown() {
+		data.Formatted = types.StringUnknown()
+		return
+	}
+
+	separator := data.GroupSeparator.ValueString()
+	if data.GroupSeparator.IsNull() {
+		separator = DEFAULT_ID_GROUP_SEPARATOR
+	}
+	grouped := groupId(id, int(data.GroupSize.ValueInt64()), separator)
+	if data.Format.IsNull() {
+		data.Formatted = types.StringValue(grouped)
+		return
+	}
+	data.Formatted = types.StringValue(renderFormat(data.Format.ValueString(), map[string]string{
+		"id":     grouped,
+		"prefix": data.Prefix.ValueString(),
+		"suffix": data.Suffix.ValueString(),
+	}))
+}
+
+// excludeCharacters returns the characters of the alphabet which are not excluded.
+func excludeCharacters(alphabet string, excluded string) []rune {
+	result := []rune{}
+	for _, r := range alphabet {
+		if !strings.ContainsRune(excluded, r) {
+			result = append(result, r)
+		}
+	}
+
+	return result
+}
+
+func (data *IdResourceModel) minEntropyBits() types.Float64 {
+	return data.MinEntropyBits
+}
+
+func (data *IdResourceModel) configuredPositions() (*positionalAlphabets, int, bool) {
+	if data.Alphabet.IsUnknown() || data.Length.IsUnknown() || data.MinLength.IsUnknown() || data.MaxLength.IsUnknown() ||
+		data.FirstCharAlphabet.IsUnknown() || data.LastCharAlphabet.IsUnknown() || data.CaseInsensitive.IsUnknown() ||
+		data.NoRepeatRun.IsUnknown() || data.ExcludeSimilar.IsUnknown() || data.ExcludeCharacters.IsUnknown() {
+		return nil, 0, false
+	}
+
+	// The shortest ids have the least entropy.
+	minLength, _ := data.lengthRange()
+	return data.positions(), minLength, true
+}
+
+// lengthRange returns the minimum and maximum lengths of the configured ids.
+func (data *IdResourceModel) lengthRange() (int, int) {
+	if !data.MinLength.IsNull() && !data.MaxLength.IsNull() {
+		return int(data.MinLength.ValueInt64()), int(data.MaxLength.ValueInt64())
+	}
+	if !data.Length.IsNull() {
+		return int(data.Length.ValueInt64()), int(data.Length.ValueInt64())
+	}
+
+	return DEFAULT_ID_LENGTH, DEFAULT_ID_LENGTH
+}
+
+var _ planmodifier.Int64 = idLengthDefault{}
+
+// idLengthDefault plans the length of ids when it is not configured: the
+// default length, or the length drawn between min_length and max_length,
+// which is only known once the id is generated.
+type idLengthDefault struct{}
+
+func (m idLengthDefault) Description(ctx context.Context) string {
+	return fmt.Sprintf("value defaults to %d, or to the length drawn between min_length and max_length", DEFAULT_ID_LENGTH)
+}
+
+func (m idLengthDefault) MarkdownDescription(ctx context.Context) string {
+	return fmt.Sprintf("value defaults to %d, or to the length drawn between `min_length` and `max_length`", DEFAULT_ID_LENGTH)
+}
+
+func (m idLengthDefault) PlanModifyInt64(ctx context.Context, req planmodifier.Int64Request, resp *planmodifier.Int64Response) {
+	if !req.ConfigValue.IsNull() || req.Plan.Raw.IsNull() {
+		return
+	}
+
+	var minLength, maxLength types.Int64
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("min_length"), &minLength)...)
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("max_length"), &maxLength)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if minLength.IsNull() && maxLength.IsNull() {
+		resp.PlanValue = types.Int64Value(DEFAULT_ID_LENGTH)
+		return
+	}
+
+	// The drawn length of an existing id is kept, unless the id is replaced.
+	if !req.StateValue.IsNull() {
+		resp.PlanValue = req.StateValue
+		return
+	}
+	resp.PlanValue = types.Int64Unknown()
+}
@@ -4,16 +4,38 @@
 package provider
 
 import (
+	"encoding/base64"
+	"encoding/hex"
 	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
 	"testing"
+	"time"
+	"unicode/utf8"
 
 	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/knownvalue"
+	"github.com/hashicorp/terraform-plugin-testing/plancheck"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+	"github.com/hashicorp/terraform-plugin-testing/tfjsonpath"
 )
 
 func testCheckLen(expectedLen int) func(input string) error {
 	return func(input string) error {
-		if len(input) != expectedLen {
-			return fmt.Errorf("expected length %d, actual length %d", expectedLen, len(input))
+		if length := utf8.RuneCountInString(input); length != expectedLen {
+			return fmt.Errorf("expected length %d, actual length %d", expectedLen, length)
+		}
+
+		return nil
+	}
+}
+
+func testCheckLenBetween(minLen int, maxLen int) func(input string) error {
+	return func(input string) error {
+		if length := utf8.RuneCountInString(input); length < minLen || length > maxLen {
+			return fmt.Errorf("expected length between %d and %d, actual length %d", minLen, maxLen, length)
 		}
 
 		return nil
@@ -26,44 +48,1068 @@ func TestAccIdResource(t *testing.T) {
 		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
 		Steps: []resource.TestStep{
 			{
-				Config: testAccIdResourceConfigEmpty(),
+				Config: testAccIdResourceConfigEmpty(),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("nanoid_id.test", "length", "21"),
+					resource.TestCheckResourceAttr("nanoid_id.test", "alphabet", "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZ_abcdefghijklmnopqrstuvwxyz-"),
+					resource.TestCheckResourceAttrWith("nanoid_id.test", "id", testCheckLen(21)),
+					resource.TestCheckResourceAttrWith("nanoid_id.test", "entropy_bits", testCheckEntropyBits(126)),
+					testCheckIdForms("nanoid_id.test"),
+				),
+			},
+			{
+				ResourceName:            "nanoid_id.test",
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"created_at", "algorithm", "generation"},
+			},
+		},
+	})
+}
+
+func TestAccIdResource_WithLength(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccIdResourceConfig(11, nil),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("nanoid_id.test", "length", "11"),
+					resource.TestCheckResourceAttr("nanoid_id.test", "alphabet", "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZ_abcdefghijklmnopqrstuvwxyz-"),
+					resource.TestCheckResourceAttrWith("nanoid_id.test", "id", testCheckLen(11)),
+				),
+			},
+			{
+				ResourceName:            "nanoid_id.test",
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"created_at", "algorithm", "generation"},
+			},
+		},
+	})
+}
+
+func TestAccIdResource_WithProviderMaxIdLength(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      testAccIdResourceConfig(65, nil),
+				ExpectError: regexp.MustCompile(`Attribute\s+length\s+must\s+be\s+at\s+most\s+64`),
+			},
+			{
+				Config:      testAccIdResourceConfigMaxIdLength(256) + testAccIdResourceConfigLengthRange(100, 300),
+				ExpectError: regexp.MustCompile(`Attribute\s+max_length\s+must\s+be\s+at\s+most\s+256`),
+			},
+			{
+				Config: testAccIdResourceConfigMaxIdLength(256) + testAccIdResourceConfig(128, nil),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("nanoid_id.test", "length", "128"),
+					resource.TestCheckResourceAttrWith("nanoid_id.test", "id", testCheckLen(128)),
+					resource.TestCheckResourceAttrWith("nanoid_id.test", "entropy_bits", testCheckEntropyBits(768)),
+				),
+			},
+		},
+	})
+}
+
+func TestAccIdResource_WithLengthRange(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      testAccIdResourceConfigLengthRange(12, 8),
+				ExpectError: regexp.MustCompile(`Attribute\s+max_length\s+must\s+be\s+at\s+least\s+min_length`),
+			},
+			{
+				Config: `
+resource "nanoid_id" "test" {
+  length     = 10
+  min_length = 8
+  max_length = 12
+}
+`,
+				ExpectError: regexp.MustCompile(`Invalid\s+Attribute\s+Combination`),
+			},
+			{
+				Config: testAccIdResourceConfigLengthRange(8, 12),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttrWith("nanoid_id.test", "id", testCheckLenBetween(8, 12)),
+					func(s *terraform.State) error {
+						attributes := s.RootModule().Resources["nanoid_id.test"].Primary.Attributes
+						if length := strconv.Itoa(utf8.RuneCountInString(attributes["id"])); attributes["length"] != length {
+							return fmt.Errorf("expected length %s, actual length %s", length, attributes["length"])
+						}
+						return nil
+					},
+				),
+			},
+			{
+				Config:   testAccIdResourceConfigLengthRange(8, 12),
+				PlanOnly: true,
+			},
+		},
+	})
+}
+
+func TestAccIdResource_CaseInsensitive(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+resource "nanoid_id" "test" {
+  case_insensitive = true
+  min_entropy_bits = 110
+}
+`,
+				ExpectError: regexp.MustCompile(`108.94\s+bits\s+of\s+entropy,\s+below\s+min_entropy_bits\s+of\s+110`),
+			},
+			{
+				Config: `
+resource "nanoid_id" "test" {
+  alphabet         = "aAbBcC"
+  length           = 16
+  case_insensitive = true
+  no_repeat_run    = true
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttrWith("nanoid_id.test", "id", testCheckLen(16)),
+					resource.TestCheckResourceAttrWith("nanoid_id.test", "id_lower", testCheckNoRepeatRun),
+					resource.TestCheckResourceAttrWith("nanoid_id.test", "entropy_bits", testCheckEntropyBits(math.Log2(3)+15)),
+					testCheckIdForms("nanoid_id.test"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccIdResource_WithFormat(t *testing.T) {
+	var id string
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+resource "nanoid_id" "test" {
+  alphabet   = "0123456789."
+  length     = 12
+  group_size = 4
+}
+`,
+				ExpectError: regexp.MustCompile(`The\s+separator\s+"\."\s+must\s+not\s+contain\s+characters\s+of\s+alphabet`),
+			},
+			{
+				Config: `
+resource "nanoid_id" "test" {
+  format = "{prefix}-{id}-{suffix}-{id}"
+}
+`,
+				ExpectError: regexp.MustCompile(`must\s+contain\s+the\s+placeholder\s+\{id\}\s+once,\s+found\s+2\s+times`),
+			},
+			{
+				Config: `
+resource "nanoid_id" "test" {
+  format = "{customer}-{id}"
+}
+`,
+				ExpectError: regexp.MustCompile(`The\s+placeholder\s+\{customer\}\s+is\s+unknown`),
+			},
+			{
+				Config: `
+resource "nanoid_id" "test" {
+  length     = 12
+  group_size = 4
+}
+`,
+				Check: resource.TestMatchResourceAttr("nanoid_id.test", "formatted", regexp.MustCompile(`^[0-9A-Za-z_-]{4}\.[0-9A-Za-z_-]{4}\.[0-9A-Za-z_-]{4}$`)),
+			},
+			{
+				Config: testAccIdResourceConfigFormat("-", "{prefix}-{id}-{suffix}"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestMatchResourceAttr("nanoid_id.test", "formatted", regexp.MustCompile(`^INV-[0-9A-Z]{4}-[0-9A-Z]{4}-[0-9A-Z]{2}-2026$`)),
+					testCheckFormatted("nanoid_id.test", "INV-%s-%s-%s-2026", &id),
+				),
+			},
+			{
+				Config: testAccIdResourceConfigFormat(" ", "{prefix} {id} {suffix}"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttrPtr("nanoid_id.test", "id", &id),
+					testCheckFormatted("nanoid_id.test", "INV %s %s %s 2026", &id),
+				),
+			},
+		},
+	})
+}
+
+func TestAccIdResource_Generation(t *testing.T) {
+	var id string
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccIdResourceConfigKeepers("a", 21),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("nanoid_id.test", "generation", "1"),
+					resource.TestCheckResourceAttr("nanoid_id.test", "algorithm", ID_ALGORITHM),
+					resource.TestCheckResourceAttrWith("nanoid_id.test", "created_at", testCheckRFC3339),
+					testExtractResourceAttr("nanoid_id.test", "id", &id),
+				),
+			},
+			{
+				Config: testAccIdResourceConfigKeepers("b", 21),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("nanoid_id.test", "generation", "2"),
+					testCheckResourceAttrChanged("nanoid_id.test", "id", &id),
+				),
+			},
+			{
+				Config: testAccIdResourceConfigKeepers("c", 21),
+				Check:  resource.TestCheckResourceAttr("nanoid_id.test", "generation", "3"),
+			},
+			{
+				Config: testAccIdResourceConfigKeepers("c", 22),
+				Check:  resource.TestCheckResourceAttr("nanoid_id.test", "generation", "1"),
+			},
+		},
+	})
+}
+
+func TestAccIdResource_KeepersReplace(t *testing.T) {
+	var id string
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccIdResourceConfigKeepersReplace(`{ image = { tag = "1.0", replicas = 3 } }`),
+				Check:  testExtractResourceAttr("nanoid_id.test", "id", &id),
+			},
+			{
+				Config: testAccIdResourceConfigKeepersReplace(`{ image = { tag = "1.1", replicas = 3 } }`),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("nanoid_id.test", plancheck.ResourceActionReplace),
+						expectReplacePaths("nanoid_id.test", "keepers.image.tag"),
+					},
+				},
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("nanoid_id.test", "generation", "1"),
+					resource.TestCheckResourceAttr("nanoid_id.test", "previous_ids.#", "0"),
+					testCheckResourceAttrChanged("nanoid_id.test", "id", &id),
+				),
+			},
+		},
+	})
+}
+
+func TestAccIdResource_NestedKeepers(t *testing.T) {
+	var id string
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccIdResourceConfigNestedKeepers(`{ image = { tag = "1.0", replicas = "3" } }`),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("nanoid_id.test", "keepers.image.tag", "1.0"),
+					testExtractResourceAttr("nanoid_id.test", "id", &id),
+				),
+			},
+			{
+				Config: testAccIdResourceConfigNestedKeepers(`{ image = { tag = "1.0", replicas = 3 } }`),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("nanoid_id.test", "generation", "1"),
+					resource.TestCheckResourceAttrPtr("nanoid_id.test", "id", &id),
+				),
+			},
+			{
+				Config: testAccIdResourceConfigNestedKeepers(`{ image = { tag = "1.1", replicas = 3 } }`),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("nanoid_id.test", "generation", "2"),
+					testCheckResourceAttrChanged("nanoid_id.test", "id", &id),
+				),
+			},
+		},
+	})
+}
+
+func TestAccIdResource_KeepersSensitive(t *testing.T) {
+	var id string
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccIdResourceConfigKeepersSensitive("v1"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("nanoid_id.test", "generation", "1"),
+					resource.TestCheckNoResourceAttr("nanoid_id.test", "keepers_sensitive"),
+					testExtractResourceAttr("nanoid_id.test", "id", &id),
+				),
+			},
+			{
+				Config: testAccIdResourceConfigKeepersSensitive("v2"),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("nanoid_id.test", plancheck.ResourceActionUpdate),
+						plancheck.ExpectUnknownValue("nanoid_id.test", tfjsonpath.New("keepers_sensitive_digest")),
+					},
+				},
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("nanoid_id.test", "generation", "2"),
+					testCheckResourceAttrChanged("nanoid_id.test", "id", &id),
+				),
+			},
+		},
+	})
+}
+
+func TestAccIdResource_Labels(t *testing.T) {
+	var id, createdAt string
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccIdResourceConfigLabels("platform"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("nanoid_id.test", "labels.owner", "platform"),
+					testExtractResourceAttr("nanoid_id.test", "id", &id),
+					testExtractResourceAttr("nanoid_id.test", "created_at", &createdAt),
+				),
+			},
+			{
+				Config: testAccIdResourceConfigLabels("payments"),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("nanoid_id.test", plancheck.ResourceActionUpdate),
+						plancheck.ExpectKnownValue("nanoid_id.test", tfjsonpath.New("id"), knownvalue.NotNull()),
+					},
+				},
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("nanoid_id.test", "labels.owner", "payments"),
+					resource.TestCheckResourceAttrPtr("nanoid_id.test", "id", &id),
+					resource.TestCheckResourceAttrPtr("nanoid_id.test", "created_at", &createdAt),
+					resource.TestCheckResourceAttr("nanoid_id.test", "generation", "1"),
+					resource.TestCheckResourceAttr("nanoid_id.test", "previous_ids.#", "0"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccIdResource_Seed(t *testing.T) {
+	var id, first string
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccIdResourceConfigSeed("a"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckNoResourceAttr("nanoid_id.test", "seed"),
+					resource.TestCheckResourceAttr("nanoid_id.test", "algorithm", SEEDED_ID_ALGORITHM),
+					// The same seed, salt, keepers and generation derive the same id, as after the state is lost.
+					resource.TestCheckResourceAttrPair("nanoid_id.test", "id", "nanoid_id.recovered", "id"),
+					testCheckResourceAttrDiffers("nanoid_id.test", "nanoid_id.salted", "id"),
+					testExtractResourceAttr("nanoid_id.test", "id", &id),
+					testExtractResourceAttr("nanoid_id.test", "id", &first),
+				),
+			},
+			{
+				Config: testAccIdResourceConfigSeed("b"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("nanoid_id.test", "generation", "2"),
+					resource.TestCheckResourceAttr("nanoid_id.test", "algorithm", SEEDED_ID_ALGORITHM),
+					testCheckResourceAttrChanged("nanoid_id.test", "id", &id),
+					resource.TestCheckResourceAttrPair("nanoid_id.test", "id", "nanoid_id.recovered", "id"),
+					testExtractResourceAttr("nanoid_id.test", "id", &id),
+				),
+			},
+			{
+				// Changing the keepers back derives a new id of the next generation.
+				Config: testAccIdResourceConfigSeed("a"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("nanoid_id.test", "generation", "3"),
+					testCheckResourceAttrChanged("nanoid_id.test", "id", &id),
+					testCheckResourceAttrChanged("nanoid_id.test", "id", &first),
+					resource.TestCheckResourceAttrPair("nanoid_id.test", "id", "nanoid_id.recovered", "id"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccIdResource_SeedRecovery(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccIdResourceConfigSeedRecovery("a", false, 0),
+			},
+			{
+				Config: testAccIdResourceConfigSeedRecovery("b", false, 0),
+				Check:  resource.TestCheckResourceAttr("nanoid_id.test", "generation", "2"),
+			},
+			{
+				// A new resource, as after the state is lost, derives the id
+				// regenerated in place again from its generation.
+				Config: testAccIdResourceConfigSeedRecovery("b", true, 2),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("nanoid_id.recovered", "generation", "2"),
+					resource.TestCheckResourceAttrPair("nanoid_id.test", "id", "nanoid_id.recovered", "id"),
+				),
+			},
+			{
+				// Removing the generation once recovered keeps the id.
+				Config:   testAccIdResourceConfigSeedRecovery("b", true, 0),
+				PlanOnly: true,
+			},
+			{
+				Config: testAccIdResourceConfigSeedRecovery("c", true, 0),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("nanoid_id.test", "generation", "3"),
+					resource.TestCheckResourceAttr("nanoid_id.recovered", "generation", "3"),
+					resource.TestCheckResourceAttrPair("nanoid_id.test", "id", "nanoid_id.recovered", "id"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccIdResource_SeedKeepersSensitive(t *testing.T) {
+	var id string
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccIdResourceConfigSeedKeepersSensitive("1"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttrPair("nanoid_id.test", "id", "nanoid_id.recovered", "id"),
+					testExtractResourceAttr("nanoid_id.test", "id", &id),
+				),
+			},
+			{
+				// Only the sensitive keepers change, so the id derives from them.
+				Config: testAccIdResourceConfigSeedKeepersSensitive("2"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("nanoid_id.test", "generation", "1"),
+					testCheckResourceAttrChanged("nanoid_id.test", "id", &id),
+					testCheckResourceAttrDiffers("nanoid_id.test", "nanoid_id.recovered", "id"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccIdResource_SeedGenerateAtPlan(t *testing.T) {
+	var id string
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				// The plan of a plan only step is checked after refreshing.
+				Config: testAccIdResourceConfigSeedGenerateAtPlan("a"),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PostApplyPostRefresh: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("nanoid_id.test", plancheck.ResourceActionCreate),
+						plancheck.ExpectKnownValue("nanoid_id.test", tfjsonpath.New("id"), knownvalue.StringRegexp(regexp.MustCompile(`^[A-Za-z0-9_-]{21}$`))),
+						plancheck.ExpectKnownValue("nanoid_id.test", tfjsonpath.New("formatted"), knownvalue.StringRegexp(regexp.MustCompile(`^[A-Za-z0-9_-]{21}$`))),
+					},
+				},
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+			},
+			{
+				Config: testAccIdResourceConfigSeedGenerateAtPlan("a"),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("nanoid_id.test", plancheck.ResourceActionCreate),
+						plancheck.ExpectKnownValue("nanoid_id.test", tfjsonpath.New("id"), knownvalue.StringRegexp(regexp.MustCompile(`^[A-Za-z0-9_-]{21}$`))),
+						plancheck.ExpectKnownValue("nanoid_id.test", tfjsonpath.New("algorithm"), knownvalue.StringExact(SEEDED_ID_ALGORITHM)),
+						plancheck.ExpectUnknownValue("nanoid_id.test", tfjsonpath.New("created_at")),
+					},
+				},
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttrPair("nanoid_id.test", "id", "nanoid_id.recovered", "id"),
+					resource.TestCheckResourceAttrWith("nanoid_id.test", "created_at", testCheckRFC3339),
+					testCheckIdForms("nanoid_id.test"),
+					testExtractResourceAttr("nanoid_id.test", "id", &id),
+				),
+			},
+			{
+				Config: testAccIdResourceConfigSeedGenerateAtPlan("b"),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("nanoid_id.test", plancheck.ResourceActionUpdate),
+						plancheck.ExpectKnownValue("nanoid_id.test", tfjsonpath.New("id"), knownvalue.StringRegexp(regexp.MustCompile(`^[A-Za-z0-9_-]{21}$`))),
+					},
+				},
+				Check: resource.ComposeAggregateTestCheckFunc(
+					testCheckResourceAttrChanged("nanoid_id.test", "id", &id),
+					resource.TestCheckResourceAttr("nanoid_id.test", "generation", "2"),
+					resource.TestCheckResourceAttr("nanoid_id.test", "algorithm", SEEDED_ID_ALGORITHM),
+					resource.TestCheckResourceAttrPair("nanoid_id.test", "id", "nanoid_id.recovered", "id"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccIdResource_WithHistorySize(t *testing.T) {
+	ids := make([]string, 4)
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccIdResourceConfigHistorySize("a", 2),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("nanoid_id.test", "previous_ids.#", "0"),
+					testExtractResourceAttr("nanoid_id.test", "id", &ids[0]),
+				),
+			},
+			{
+				Config: testAccIdResourceConfigHistorySize("b", 2),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("nanoid_id.test", plancheck.ResourceActionUpdate),
+					},
+				},
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("nanoid_id.test", "generation", "2"),
+					resource.TestCheckResourceAttr("nanoid_id.test", "previous_ids.#", "1"),
+					resource.TestCheckResourceAttrPtr("nanoid_id.test", "previous_ids.0", &ids[0]),
+					testExtractResourceAttr("nanoid_id.test", "id", &ids[1]),
+				),
+			},
+			{
+				Config: testAccIdResourceConfigHistorySize("c", 2),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("nanoid_id.test", "previous_ids.#", "2"),
+					resource.TestCheckResourceAttrPtr("nanoid_id.test", "previous_ids.0", &ids[1]),
+					resource.TestCheckResourceAttrPtr("nanoid_id.test", "previous_ids.1", &ids[0]),
+					testExtractResourceAttr("nanoid_id.test", "id", &ids[2]),
+				),
+			},
+			{
+				Config: testAccIdResourceConfigHistorySize("d", 2),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("nanoid_id.test", "previous_ids.#", "2"),
+					resource.TestCheckResourceAttrPtr("nanoid_id.test", "previous_ids.0", &ids[2]),
+					resource.TestCheckResourceAttrPtr("nanoid_id.test", "previous_ids.1", &ids[1]),
+					testExtractResourceAttr("nanoid_id.test", "id", &ids[3]),
+				),
+			},
+			{
+				Config: testAccIdResourceConfigHistorySize("d", 1),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttrPtr("nanoid_id.test", "id", &ids[3]),
+					resource.TestCheckResourceAttr("nanoid_id.test", "previous_ids.#", "1"),
+					resource.TestCheckResourceAttrPtr("nanoid_id.test", "previous_ids.0", &ids[2]),
+				),
+			},
+		},
+	})
+}
+
+func TestAccIdResource_GenerateAtPlan(t *testing.T) {
+	var id string
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+resource "nanoid_id" "test" {
+  generate_at_plan = true
+}
+`,
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile(`Without\s+seed,\s+generate_at_plan\s+only\s+plans\s+the\s+ids\s+regenerated\s+in\s+place`),
+			},
+			{
+				Config: testAccIdResourceConfigGenerateAtPlan("a"),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectUnknownValue("nanoid_id.test", tfjsonpath.New("id")),
+					},
+				},
+				Check: testExtractResourceAttr("nanoid_id.test", "id", &id),
+			},
+			{
+				Config: testAccIdResourceConfigGenerateAtPlan("b"),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("nanoid_id.test", plancheck.ResourceActionUpdate),
+						plancheck.ExpectKnownValue("nanoid_id.test", tfjsonpath.New("id"), knownvalue.StringRegexp(regexp.MustCompile(`^.{21}$`))),
+						plancheck.ExpectKnownValue("nanoid_id.test", tfjsonpath.New("formatted"), knownvalue.StringRegexp(regexp.MustCompile(`^.{21}$`))),
+						plancheck.ExpectKnownValue("nanoid_id.test", tfjsonpath.New("generation"), knownvalue.Int64Exact(2)),
+					},
+				},
+				Check: resource.ComposeAggregateTestCheckFunc(
+					testCheckResourceAttrChanged("nanoid_id.test", "id", &id),
+					resource.TestCheckResourceAttr("nanoid_id.test", "generation", "2"),
+					resource.TestCheckResourceAttrWith("nanoid_id.test", "created_at", testCheckRFC3339),
+					testCheckIdForms("nanoid_id.test"),
+				),
+			},
+			{
+				Config: testAccIdResourceConfigGenerateAtPlan("c"),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectKnownValue("nanoid_id.test", tfjsonpath.New("id"), knownvalue.StringRegexp(regexp.MustCompile(`^.{21}$`))),
+					},
+				},
+			},
+		},
+	})
+}
+
+func TestAccIdResource_WithUnicodeAlphabet(t *testing.T) {
+	alphabet := "日月火水木金土山川田🦊🐙🌵🍄"
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccIdResourceConfig(12, &alphabet),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("nanoid_id.test", "length", "12"),
+					resource.TestCheckResourceAttrWith("nanoid_id.test", "id", testCheckLen(12)),
+					resource.TestCheckResourceAttrWith("nanoid_id.test", "id", testCheckAlphabet(alphabet)),
+					resource.TestCheckResourceAttrWith("nanoid_id.test", "entropy_bits", testCheckEntropyBits(12*math.Log2(14))),
+				),
+			},
+			{
+				ResourceName:            "nanoid_id.test",
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"alphabet", "effective_alphabet", "entropy_bits", "created_at", "algorithm", "generation"},
+			},
+		},
+	})
+}
+
+func TestAccIdResource_WithLargeAlphabet(t *testing.T) {
+	var runes []rune
+	for r := rune(0x4E00); r < 0x4E00+1000; r++ {
+		runes = append(runes, r)
+	}
+	alphabet := string(runes)
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(`
+resource "nanoid_id" "test" {
+  alphabet      = %q
+  length        = 8
+  checksum      = "luhn"
+  no_repeat_run = true
+}
+`, alphabet),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttrWith("nanoid_id.test", "id", testCheckLen(9)),
+					resource.TestCheckResourceAttrWith("nanoid_id.test", "id", testCheckAlphabet(alphabet)),
+					resource.TestCheckResourceAttrWith("nanoid_id.test", "id", testCheckNoRepeatRun),
+					resource.TestCheckResourceAttrWith("nanoid_id.test", "id", testCheckChecksum("luhn", alphabet)),
+					resource.TestCheckResourceAttrWith("nanoid_id.test", "entropy_bits", testCheckEntropyBits(math.Log2(1000)+7*math.Log2(999))),
+				),
+			},
+		},
+	})
+}
+
+func TestAccIdResource_WithChecksum(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+resource "nanoid_id" "test" {
+  alphabet            = "0123456789"
+  first_char_alphabet = "ABC1"
+  checksum            = "luhn"
+}
+`,
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile(`The\s+character\s+'A'\s+of\s+first_char_alphabet\s+is\s+not\s+part\s+of\s+the\s+alphabet`),
+			},
+			{
+				Config:      testAccIdResourceConfigChecksum("verhoeff"),
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile(`The\s+verhoeff\s+checksum\s+cannot\s+be\s+computed\s+over\s+the\s+alphabet:\s+the\s+verhoeff\s+algorithm\s+requires\s+an\s+alphabet\s+of\s+exactly\s+10\s+characters,\s+got\s+64`),
+			},
+			{
+				Config: `
+resource "nanoid_id" "test" {
+  alphabet = "abcdef"
+  checksum = "damm"
+}
+`,
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile(`the\s+damm\s+algorithm\s+does\s+not\s+support\s+alphabets\s+of\s+6\s+characters`),
+			},
+			{
+				Config: testAccIdResourceConfigChecksum("damm"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("nanoid_id.test", "length", "21"),
+					resource.TestCheckResourceAttr("nanoid_id.test", "checksum", "damm"),
+					resource.TestCheckResourceAttrWith("nanoid_id.test", "id", testCheckLen(22)),
+					resource.TestCheckResourceAttrWith("nanoid_id.test", "id", testCheckChecksum("damm", DEFAULT_ID_ALPHABET)),
+				),
+			},
+			{
+				Config: `
+resource "nanoid_id" "test" {
+  alphabet            = "0123456789"
+  first_char_alphabet = "123"
+  checksum            = "luhn"
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestMatchResourceAttr("nanoid_id.test", "id", regexp.MustCompile(`^[1-3][0-9]{21}$`)),
+					resource.TestCheckResourceAttrWith("nanoid_id.test", "id", testCheckChecksum("luhn", "0123456789")),
+				),
+			},
+		},
+	})
+}
+
+func TestAccIdResource_WithPositionalAlphabets(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+resource "nanoid_id" "test" {
+  alphabet            = "0123456789"
+  length              = 12
+  first_char_alphabet = "ABCDEF"
+  last_char_alphabet  = "XYZ"
+  no_repeat_run       = true
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestMatchResourceAttr("nanoid_id.test", "id", regexp.MustCompile(`^[A-F][0-9]{10}[XYZ]$`)),
+					resource.TestCheckResourceAttrWith("nanoid_id.test", "id", testCheckNoRepeatRun),
+					resource.TestCheckResourceAttrWith("nanoid_id.test", "entropy_bits", testCheckEntropyBits(math.Log2(6)+math.Log2(10)+9*math.Log2(9)+math.Log2(3))),
+				),
+			},
+		},
+	})
+}
+
+func TestAccIdResource_WithExcludeSimilar(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+resource "nanoid_id" "test" {
+  exclude_similar    = true
+  exclude_characters = "_-"
+  checksum           = "luhn"
+}
+`,
 				Check: resource.ComposeAggregateTestCheckFunc(
-					resource.TestCheckResourceAttr("nanoid_id.test", "length", "21"),
-					resource.TestCheckResourceAttr("nanoid_id.test", "alphabet", "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZ_abcdefghijklmnopqrstuvwxyz-"),
-					resource.TestCheckResourceAttrWith("nanoid_id.test", "id", testCheckLen(21)),
+					resource.TestCheckResourceAttr("nanoid_id.test", "alphabet", DEFAULT_ID_ALPHABET),
+					resource.TestCheckResourceAttr("nanoid_id.test", "effective_alphabet", "346789ABCDEFGHJKLMNPQRTUVWXYabcdefghijkmnopqrstuvwxyz"),
+					resource.TestMatchResourceAttr("nanoid_id.test", "id", regexp.MustCompile(`^[346789ABCDEFGHJKLMNPQRTUVWXYabcdefghijkmnopqrstuvwxyz]{22}$`)),
+					resource.TestCheckResourceAttrWith("nanoid_id.test", "id", testCheckChecksum("luhn", "346789ABCDEFGHJKLMNPQRTUVWXYabcdefghijkmnopqrstuvwxyz")),
+					resource.TestCheckResourceAttrWith("nanoid_id.test", "entropy_bits", testCheckEntropyBits(21*math.Log2(53))),
+				),
+			},
+		},
+	})
+}
+
+func TestAccIdResource_WithMinEntropyBits(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      testAccIdResourceConfigMinEntropyBits(21, 128),
+				ExpectError: regexp.MustCompile(`126.00\s+bits\s+of\s+entropy,\s+below\s+min_entropy_bits\s+of\s+128.\s+Set\s+length\s+to\s+at\s+least\s+22`),
+			},
+			{
+				Config:      testAccIdResourceConfigMinEntropyBits(21, 500),
+				ExpectError: regexp.MustCompile(`No\s+length\s+up\s+to\s+64\s+reaches\s+it`),
+			},
+			{
+				Config: testAccIdResourceConfigMinEntropyBits(21, 120),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("nanoid_id.test", "min_entropy_bits", "120"),
+					resource.TestCheckResourceAttr("nanoid_id.test", "entropy_bits", "126"),
 				),
 			},
 			{
-				ResourceName:      "nanoid_id.test",
-				ImportState:       true,
-				ImportStateVerify: true,
+				Config: testAccIdResourceConfigMinEntropyBits(21, 126),
+				Check:  resource.TestCheckResourceAttr("nanoid_id.test", "min_entropy_bits", "126"),
 			},
 		},
 	})
 }
 
-func TestAccIdResource_WithLength(t *testing.T) {
+func TestAccIdResource_DuplicateAlphabetCharacter(t *testing.T) {
+	alphabet := DEFAULT_ID_ALPHABET + "-"
 	resource.Test(t, resource.TestCase{
 		PreCheck:                 func() { testAccPreCheck(t) },
 		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
 		Steps: []resource.TestStep{
 			{
-				Config: testAccIdResourceConfig(11, nil),
+				Config:      testAccIdResourceConfig(21, &alphabet),
+				ExpectError: regexp.MustCompile(`The\s+character\s+'-'\s+appears\s+at\s+positions\s+63\s+and\s+64`),
+			},
+		},
+	})
+}
+
+func TestAccIdResource_ExcludeEveryCharacter(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+resource "nanoid_id" "test" {
+  alphabet        = "0O1lI"
+  exclude_similar = true
+}
+`,
+				ExpectError: regexp.MustCompile(`Every\s+character\s+of\s+alphabet\s+is\s+excluded`),
+			},
+		},
+	})
+}
+
+func TestAccIdResource_LastCharAlphabetConflictsWithChecksum(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+resource "nanoid_id" "test" {
+  checksum           = "luhn"
+  last_char_alphabet = "abc"
+}
+`,
+				ExpectError: regexp.MustCompile(`Invalid\s+Attribute\s+Combination`),
+			},
+		},
+	})
+}
+
+func TestAccIdResource_WithConstraints(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccIdResourceConfigConstraints(`[0-9].*[0-9]`, `^[0-9]`, 100),
 				Check: resource.ComposeAggregateTestCheckFunc(
-					resource.TestCheckResourceAttr("nanoid_id.test", "length", "11"),
-					resource.TestCheckResourceAttr("nanoid_id.test", "alphabet", "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZ_abcdefghijklmnopqrstuvwxyz-"),
-					resource.TestCheckResourceAttrWith("nanoid_id.test", "id", testCheckLen(11)),
+					resource.TestCheckResourceAttr("nanoid_id.test", "max_attempts", "100"),
+					resource.TestMatchResourceAttr("nanoid_id.test", "id", regexp.MustCompile(`^[^0-9].*[0-9].*[0-9]`)),
 				),
 			},
 			{
-				ResourceName:      "nanoid_id.test",
-				ImportState:       true,
-				ImportStateVerify: true,
+				Config: testAccIdResourceConfigConstraints(`[0-9].*[0-9]`, `^[0-9]`, 200),
+				Check:  resource.TestCheckResourceAttr("nanoid_id.test", "max_attempts", "200"),
+			},
+			{
+				ResourceName:            "nanoid_id.test",
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"must_match", "must_not_match", "max_attempts", "created_at", "algorithm", "generation"},
+			},
+		},
+	})
+}
+
+func TestAccIdResource_UnsatisfiableConstraints(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      testAccIdResourceConfigConstraints(`^[0-9]{21}$`, `^_`, 1000),
+				ExpectError: regexp.MustCompile(`None\s+of\s+10000\s+sampled\s+ids\s+satisfied`),
+			},
+			{
+				Config:      testAccIdResourceConfigConstraints(`^[0-9]{2}`, `^_`, 10),
+				ExpectError: regexp.MustCompile(`set\s+max_attempts\s+to\s+at\s+least`),
+			},
+			{
+				Config:      testAccIdResourceConfigConstraints(`[0-9`, `^_`, 1000),
+				ExpectError: regexp.MustCompile(`Invalid\s+regular\s+expression`),
+			},
+		},
+	})
+}
+
+func TestAccIdResource_ConstraintsBoundary(t *testing.T) {
+	// The ids sampled with the fixed seed satisfy ^[a-f] at a rate of 9.25%,
+	// so 48 attempts succeed with a probability of at least 99% and 47 do not.
+	// The accepted configuration is only planned, as its apply still fails in
+	// about 1% of the runs.
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      testAccIdResourceConfigConstraints(`^[a-f]`, `^_`, 47),
+				ExpectError: regexp.MustCompile(`An\s+estimated\s+9\.25%\s+of\s+the\s+ids(.|\s)*set\s+max_attempts\s+to\s+at\s+least\s+48\.`),
+			},
+			{
+				Config:             testAccIdResourceConfigConstraints(`^[a-f]`, `^_`, 48),
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
 			},
 		},
 	})
 }
 
+func testCheckIdForms(name string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		attributes := s.RootModule().Resources[name].Primary.Attributes
+		id := attributes["id"]
+		expected := map[string]string{
+			"id_upper":     strings.ToUpper(id),
+			"id_lower":     strings.ToLower(id),
+			"id_base64url": base64.RawURLEncoding.EncodeToString([]byte(id)),
+			"id_hex":       hex.EncodeToString([]byte(id)),
+		}
+		for attribute, value := range expected {
+			if attributes[attribute] != value {
+				return fmt.Errorf("expected %s %q, actual %q", attribute, value, attributes[attribute])
+			}
+		}
+
+		return nil
+	}
+}
+
+// testCheckFormatted checks that the formatted id is the id split into groups
+// of 4 characters rendered with the format, and records the id.
+func testCheckFormatted(name string, format string, id *string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		attributes := s.RootModule().Resources[name].Primary.Attributes
+		*id = attributes["id"]
+		groups := strings.Split(groupId(*id, 4, "\x00"), "\x00")
+		args := make([]any, len(groups))
+		for i, group := range groups {
+			args[i] = group
+		}
+		if expected := fmt.Sprintf(format, args...); attributes["formatted"] != expected {
+			return fmt.Errorf("expected formatted %q, actual %q", expected, attributes["formatted"])
+		}
+
+		return nil
+	}
+}
+
+func testCheckRFC3339(input string) error {
+	_, err := time.Parse(time.RFC3339, input)
+	return err
+}
+
+func testExtractResourceAttr(name string, attribute string, value *string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		*value = s.RootModule().Resources[name].Primary.Attributes[attribute]
+		return nil
+	}
+}
+
+func testCheckResourceAttrChanged(name string, attribute string, previous *string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		if value := s.RootModule().Resources[name].Primary.Attributes[attribute]; value == *previous {
+			return fmt.Errorf("expected %s to change from %q", attribute, *previous)
+		}
+
+		return nil
+	}
+}
+
+func testCheckResourceAttrDiffers(name string, otherName string, attribute string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		value := s.RootModule().Resources[name].Primary.Attributes[attribute]
+		if other := s.RootModule().Resources[otherName].Primary.Attributes[attribute]; value == other {
+			return fmt.Errorf("expected %s of %s and %s to differ, both are %q", attribute, name, otherName, value)
+		}
+
+		return nil
+	}
+}
+
+func testCheckNoRepeatRun(input string) error {
+	runes := []rune(input)
+	for i := 1; i < len(runes); i++ {
+		if runes[i] == runes[i-1] {
+			return fmt.Errorf("expected %q not to repeat the character %q", input, runes[i])
+		}
+	}
+
+	return nil
+}
+
+func testCheckAlphabet(alphabet string) func(input string) error {
+	return func(input string) error {
+		for i, r := range []rune(input) {
+			if !strings.ContainsRune(alphabet, r) {
+				return fmt.Errorf("expected the character %q at position %d of %q to be part of the alphabet", r, i, input)
+			}
+		}
+
+		return nil
+	}
+}
+
+func testCheckEntropyBits(expected float64) func(input string) error {
+	return func(input string) error {
+		bits, err := strconv.ParseFloat(input, 64)
+		if err != nil {
+			return err
+		}
+		if math.Abs(bits-expected) > 1e-6 {
+			return fmt.Errorf("expected %f bits of entropy, got %f", expected, bits)
+		}
+
+		return nil
+	}
+}
+
+func testCheckChecksum(algorithm string, alphabet string) func(input string) error {
+	return func(input string) error {
+		valid, err := verifyChecksum(algorithm, alphabet, input)
+		if err != nil {
+			return err
+		}
+		if !valid {
+			return fmt.Errorf("expected %q to have a valid %s checksum", input, algorithm)
+		}
+
+		return nil
+	}
+}
+
 func testAccIdResourceConfig(length int, alphabet *string) string {
 	lengthStr := fmt.Sprintf("length = %d", length)
 	alphabetStr := ""
@@ -81,3 +1127,236 @@ resource "nanoid_id" "test" {
 func testAccIdResourceConfigEmpty() string {
 	return `resource "nanoid_id" "test" {}`
 }
+
+func testAccIdResourceConfigChecksum(checksum string) string {
+	return fmt.Sprintf(`
+resource "nanoid_id" "test" {
+  checksum = %q
+}
+`, checksum)
+}
+
+func testAccIdResourceConfigConstraints(mustMatch string, mustNotMatch string, maxAttempts int) string {
+	return fmt.Sprintf(`
+resource "nanoid_id" "test" {
+  must_match     = %q
+  must_not_match = %q
+  max_attempts   = %d
+}
+`, mustMatch, mustNotMatch, maxAttempts)
+}
+
+func testAccIdResourceConfigMinEntropyBits(length int, minEntropyBits float64) string {
+	return fmt.Sprintf(`
+resource "nanoid_id" "test" {
+  length           = %d
+  min_entropy_bits = %g
+}
+`, length, minEntropyBits)
+}
+
+func testAccIdResourceConfigMaxIdLength(maxIdLength int) string {
+	return fmt.Sprintf(`
+provider "nanoid" {
+  max_id_length = %d
+}
+`, maxIdLength)
+}
+
+func testAccIdResourceConfigLengthRange(minLength int, maxLength int) string {
+	return fmt.Sprintf(`
+resource "nanoid_id" "test" {
+  min_length = %d
+  max_length = %d
+}
+`, minLength, maxLength)
+}
+
+func testAccIdResourceConfigFormat(separator string, format string) string {
+	return fmt.Sprintf(`
+resource "nanoid_id" "test" {
+  alphabet        = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZ"
+  length          = 10
+  group_size      = 4
+  group_separator = %q
+  format          = %q
+  prefix          = "INV"
+  suffix          = "2026"
+}
+`, separator, format)
+}
+
+func testAccIdResourceConfigKeepers(keeper string, length int) string {
+	return fmt.Sprintf(`
+resource "nanoid_id" "test" {
+  length              = %d
+  regenerate_in_place = true
+  keepers = {
+    keeper = %q
+  }
+}
+`, length, keeper)
+}
+
+func testAccIdResourceConfigKeepersReplace(keepers string) string {
+	return fmt.Sprintf(`
+resource "nanoid_id" "test" {
+  keepers = %s
+}
+`, keepers)
+}
+
+func testAccIdResourceConfigNestedKeepers(keepers string) string {
+	return fmt.Sprintf(`
+resource "nanoid_id" "test" {
+  regenerate_in_place = true
+  keepers             = %s
+}
+`, keepers)
+}
+
+func testAccIdResourceConfigKeepersSensitive(version string) string {
+	return fmt.Sprintf(`
+resource "nanoid_id" "test" {
+  regenerate_in_place = true
+  keepers_sensitive = {
+    secret_version = %q
+  }
+}
+`, version)
+}
+
+func testAccIdResourceConfigLabels(owner string) string {
+	return fmt.Sprintf(`
+resource "nanoid_id" "test" {
+  history_size = 2
+  labels = {
+    owner   = %q
+    purpose = "invoices"
+  }
+}
+`, owner)
+}
+
+func testAccIdResourceConfigSeed(keeper string) string {
+	return fmt.Sprintf(`
+resource "nanoid_id" "test" {
+  seed                = "correct horse battery staple"
+  regenerate_in_place = true
+  keepers = {
+    keeper = %[1]q
+  }
+}
+
+resource "nanoid_id" "recovered" {
+  seed                = "correct horse battery staple"
+  regenerate_in_place = true
+  keepers = {
+    keeper = %[1]q
+  }
+}
+
+resource "nanoid_id" "salted" {
+  seed                = "correct horse battery staple"
+  regenerate_in_place = true
+  salt                = "eu-west-1"
+  keepers = {
+    keeper = %[1]q
+  }
+}
+`, keeper)
+}
+
+// testAccIdResourceConfigSeedRecovery configures the recovered resource, with
+// its generation when not 0, when recovered is set.
+func testAccIdResourceConfigSeedRecovery(keeper string, recovered bool, generation int) string {
+	config := fmt.Sprintf(`
+resource "nanoid_id" "test" {
+  seed                = "correct horse battery staple"
+  regenerate_in_place = true
+  keepers = {
+    keeper = %q
+  }
+}
+`, keeper)
+	if !recovered {
+		return config
+	}
+
+	attribute := ""
+	if generation != 0 {
+		attribute = fmt.Sprintf("generation          = %d", generation)
+	}
+	return config + fmt.Sprintf(`
+resource "nanoid_id" "recovered" {
+  seed                = "correct horse battery staple"
+  regenerate_in_place = true
+  %s
+  keepers = {
+    keeper = %q
+  }
+}
+`, attribute, keeper)
+}
+
+func testAccIdResourceConfigSeedKeepersSensitive(version string) string {
+	return fmt.Sprintf(`
+resource "nanoid_id" "test" {
+  seed = "correct horse battery staple"
+  keepers_sensitive = {
+    secret_version = %q
+  }
+}
+
+resource "nanoid_id" "recovered" {
+  seed = "correct horse battery staple"
+  keepers_sensitive = {
+    secret_version = "1"
+  }
+}
+`, version)
+}
+
+func testAccIdResourceConfigSeedGenerateAtPlan(keeper string) string {
+	return fmt.Sprintf(`
+resource "nanoid_id" "test" {
+  seed                = "correct horse battery staple"
+  generate_at_plan    = true
+  regenerate_in_place = true
+  keepers = {
+    keeper = %[1]q
+  }
+}
+
+resource "nanoid_id" "recovered" {
+  seed                = "correct horse battery staple"
+  regenerate_in_place = true
+  keepers = {
+    keeper = %[1]q
+  }
+}
+`, keeper)
+}
+
+func testAccIdResourceConfigHistorySize(keeper string, historySize int) string {
+	return fmt.Sprintf(`
+resource "nanoid_id" "test" {
+  history_size = %d
+  keepers = {
+    keeper = %q
+  }
+}
+`, historySize, keeper)
+}
+
+func testAccIdResourceConfigGenerateAtPlan(keeper string) string {
+	return fmt.Sprintf(`
+resource "nanoid_id" "test" {
+  generate_at_plan    = true
+  regenerate_in_place = true
+  keepers = {
+    keeper = %q
+  }
+}
+`, keeper)
+}
@@ -0,0 +1,291 @@
+// Copyright (c) The Nanoid Provider for Terraform Authors
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"net/netip"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64default"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/mapplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	gonanoid "github.com/matoous/go-nanoid"
+)
+
+// HEX_ALPHABET generates random bits as hexadecimal digits.
+const HEX_ALPHABET = "0123456789abcdef"
+
+// ULA_GLOBAL_ID_LENGTH is the number of hexadecimal digits of the 40-bit global id.
+const ULA_GLOBAL_ID_LENGTH = 10
+const DEFAULT_ULA_SUBNET_COUNT = 0
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ resource.Resource = &Ipv6UlaResource{}
+var _ resource.ResourceWithImportState = &Ipv6UlaResource{}
+
+func NewIpv6UlaResource() resource.Resource {
+	return &Ipv6UlaResource{}
+}
+
+// Ipv6UlaResource defines the resource implementation.
+type Ipv6UlaResource struct{}
+
+// Ipv6UlaResourceModel describes the resource data model.
+type Ipv6UlaResourceModel struct {
+	Id                     types.String  `tfsdk:"id"`
+	GlobalId               types.String  `tfsdk:"global_id"`
+	Prefix                 types.String  `tfsdk:"prefix"`
+	SubnetCount            types.Int64   `tfsdk:"subnet_count"`
+	Subnets                types.List    `tfsdk:"subnets"`
+	Keepers                types.Map     `tfsdk:"keepers"`
+	KeepersSensitive       types.Dynamic `tfsdk:"keepers_sensitive"`
+	KeepersSensitiveDigest types.String  `tfsdk:"keepers_sensitive_digest"`
+}
+
+func (d *Ipv6UlaResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_ipv6_ula"
+}
+
+func (d *Ipv6UlaResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "The ipv6_ula resource generates a random 40-bit global id and the resulting IPv6 unique local address `/48` prefix, " +
+			"as described by [RFC 4193](https://www.rfc-editor.org/rfc/rfc4193).\n\n" +
+			"Existing prefixes can be imported with their `/48` notation, for example `fd12:3456:789a::/48`.",
+		Attributes: map[string]schema.Attribute{
+			"subnet_count": schema.Int64Attribute{
+				MarkdownDescription: fmt.Sprintf("The number of `/64` subnets to derive from the prefix, by subnet id.\n"+
+					"Should be between 0 and 256.\n"+
+					"The default value is %d.", DEFAULT_ULA_SUBNET_COUNT),
+				Optional: true,
+				Computed: true,
+				Default:  int64default.StaticInt64(DEFAULT_ULA_SUBNET_COUNT),
+				Validators: []validator.Int64{
+					int64validator.Between(0, 256),
+				},
+			},
+
+			"keepers": schema.MapAttribute{
+				Description: "Arbitrary map of values that, when changed, will trigger recreation of " +
+					"resource. See [the main provider documentation](../index.html) for more information.",
+				ElementType: types.StringType,
+				Optional:    true,
+				PlanModifiers: []planmodifier.Map{
+					mapplanmodifier.RequiresReplaceIfConfigured(),
+				},
+			},
+
+			"keepers_sensitive": schema.DynamicAttribute{
+				MarkdownDescription: "Arbitrary sensitive values, such as credential versions, that, when changed, will trigger recreation of resource. " +
+					"The values are write-only: only their salted digest is stored, in `keepers_sensitive_digest`. Write-only attributes require Terraform 1.11 or later.",
+				Optional:  true,
+				Sensitive: true,
+				WriteOnly: true,
+			},
+
+			"keepers_sensitive_digest": schema.StringAttribute{
+				MarkdownDescription: "The salted SHA-256 digest of `keepers_sensitive`, of the form `sha256:<salt>:<digest>`, " +
+					"against which their configured values are compared.",
+				Computed: true,
+				PlanModifiers: []planmodifier.String{
+					keepersSensitiveDigestModifier{requiresReplace: true},
+				},
+			},
+
+			"global_id": schema.StringAttribute{
+				MarkdownDescription: "The generated 40-bit global id, as 10 hexadecimal digits.",
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+
+			"prefix": schema.StringAttribute{
+				MarkdownDescription: "The `/48` prefix made of `fd` followed by the global id.",
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+
+			"subnets": schema.ListAttribute{
+				MarkdownDescription: "The `/64` subnets of the prefix, the subnet at index `i` having the subnet id `i`.",
+				ElementType:         types.StringType,
+				Computed:            true,
+			},
+
+			"id": schema.StringAttribute{
+				MarkdownDescription: "The `/48` prefix.",
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+		},
+	}
+}
+
+func (d *Ipv6UlaResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	_, ok := req.ProviderData.(*NanoidProviderData)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *provider.NanoidProviderData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+}
+
+func (r *Ipv6UlaResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data Ipv6UlaResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	globalId, err := gonanoid.Generate(HEX_ALPHABET, ULA_GLOBAL_ID_LENGTH)
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to generate id", fmt.Sprintf("Failed to generate id: %s.", err))
+		return
+	}
+
+	prefix, err := ulaPrefix(globalId)
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to generate id", fmt.Sprintf("Failed to generate id: %s.", err))
+		return
+	}
+
+	data.GlobalId = types.StringValue(globalId)
+	data.Prefix = types.StringValue(prefix.String())
+	data.Id = data.Prefix
+	resp.Diagnostics.Append(data.deriveSubnets(ctx, prefix)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(setKeepersSensitiveDigest(ctx, req.Config, &data.KeepersSensitiveDigest)...)
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (d *Ipv6UlaResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data Ipv6UlaResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *Ipv6UlaResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var data Ipv6UlaResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	prefix, err := netip.ParsePrefix(data.Prefix.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Invalid prefix", fmt.Sprintf("Invalid prefix: %s.", err))
+		return
+	}
+
+	// The subnets only depend on the prefix, so a change of the subnet count
+	// is applied in place.
+	resp.Diagnostics.Append(data.deriveSubnets(ctx, prefix)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(setKeepersSensitiveDigest(ctx, req.Config, &data.KeepersSensitiveDigest)...)
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *Ipv6UlaResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var data Ipv6UlaResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+func (r *Ipv6UlaResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	prefix, err := netip.ParsePrefix(req.ID)
+	if err != nil {
+		resp.Diagnostics.AddError("Invalid id", fmt.Sprintf("The id must be an IPv6 prefix: %s.", err))
+		return
+	}
+
+	addr := prefix.Addr()
+	bytes := addr.As16()
+	if !addr.Is6() || addr.Is4In6() || prefix.Bits() != 48 || bytes[0] != 0xfd {
+		resp.Diagnostics.AddError("Invalid id", "The id must be a locally assigned unique local address /48 prefix, within fd00::/8.")
+		return
+	}
+	if prefix.Masked() != prefix {
+		resp.Diagnostics.AddError("Invalid id", fmt.Sprintf("The id must not have bits set after the /48 prefix, expected %q.", prefix.Masked()))
+		return
+	}
+
+	state := &Ipv6UlaResourceModel{
+		Id:                     types.StringValue(prefix.String()),
+		GlobalId:               types.StringValue(hex.EncodeToString(bytes[1:6])),
+		Prefix:                 types.StringValue(prefix.String()),
+		SubnetCount:            types.Int64Value(DEFAULT_ULA_SUBNET_COUNT),
+		Keepers:                types.MapNull(types.StringType),
+		KeepersSensitive:       types.DynamicNull(),
+		KeepersSensitiveDigest: types.StringNull(),
+	}
+	resp.Diagnostics.Append(state.deriveSubnets(ctx, prefix)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	diags := resp.State.Set(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+// ulaPrefix returns the fd00::/8 prefix of the hexadecimal global id.
+func ulaPrefix(globalId string) (netip.Prefix, error) {
+	id, err := hex.DecodeString(globalId)
+	if err != nil || len(id) != 5 {
+		return netip.Prefix{}, fmt.Errorf("the global id %q must be 10 hexadecimal digits", globalId)
+	}
+
+	var bytes [16]byte
+	bytes[0] = 0xfd
+	copy(bytes[1:6], id)
+	return netip.PrefixFrom(netip.AddrFrom16(bytes), 48), nil
+}
+
+// deriveSubnets computes the /64 subnets of the prefix up to the subnet count.
+func (data *Ipv6UlaResourceModel) deriveSubnets(ctx context.Context, prefix netip.Prefix) (diags diag.Diagnostics) {
+	bytes := prefix.Addr().As16()
+	subnets := make([]string, 0, data.SubnetCount.ValueInt64())
+	for i := int64(0); i < data.SubnetCount.ValueInt64(); i++ {
+		binary.BigEndian.PutUint16(bytes[6:8], uint16(i))
+		subnets = append(subnets, netip.PrefixFrom(netip.AddrFrom16(bytes), 64).String())
+	}
+
+	data.Subnets, diags = types.ListValueFrom(ctx, types.StringType, subnets)
+	return diags
+}
@@ -0,0 +1,108 @@
+// Copyright (c) The Nanoid Provider for Terraform Authors
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccIpv6UlaResource(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccIpv6UlaResourceConfigEmpty(),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestMatchResourceAttr("nanoid_ipv6_ula.test", "global_id", regexp.MustCompile(`^[0-9a-f]{10}$`)),
+					resource.TestMatchResourceAttr("nanoid_ipv6_ula.test", "prefix", regexp.MustCompile(`^fd[0-9a-f]{2}:[0-9a-f:]+/48$`)),
+					resource.TestCheckResourceAttrPair("nanoid_ipv6_ula.test", "id", "nanoid_ipv6_ula.test", "prefix"),
+					resource.TestCheckResourceAttr("nanoid_ipv6_ula.test", "subnets.#", "0"),
+				),
+			},
+			{
+				ResourceName:      "nanoid_ipv6_ula.test",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func TestAccIpv6UlaResource_WithSubnets(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccIpv6UlaResourceConfig(2),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("nanoid_ipv6_ula.test", "subnets.#", "2"),
+					resource.TestMatchResourceAttr("nanoid_ipv6_ula.test", "subnets.1", regexp.MustCompile(`^fd[0-9a-f]{2}:[0-9a-f:]+:1::/64$`)),
+				),
+			},
+			{
+				Config: testAccIpv6UlaResourceConfig(3),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("nanoid_ipv6_ula.test", "subnets.#", "3"),
+					resource.TestMatchResourceAttr("nanoid_ipv6_ula.test", "subnets.2", regexp.MustCompile(`:2::/64$`)),
+				),
+			},
+		},
+	})
+}
+
+func TestAccIpv6UlaResource_Import(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:             testAccIpv6UlaResourceConfigEmpty(),
+				ResourceName:       "nanoid_ipv6_ula.test",
+				ImportState:        true,
+				ImportStateId:      "fd12:3456:789a::/48",
+				ImportStatePersist: true,
+			},
+			{
+				Config: testAccIpv6UlaResourceConfig(1),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("nanoid_ipv6_ula.test", "global_id", "123456789a"),
+					resource.TestCheckResourceAttr("nanoid_ipv6_ula.test", "prefix", "fd12:3456:789a::/48"),
+					resource.TestCheckResourceAttr("nanoid_ipv6_ula.test", "subnets.0", "fd12:3456:789a::/64"),
+				),
+			},
+			{
+				Config:        testAccIpv6UlaResourceConfigEmpty(),
+				ResourceName:  "nanoid_ipv6_ula.test",
+				ImportState:   true,
+				ImportStateId: "fc12:3456:789a::/48",
+				ExpectError:   regexp.MustCompile(`within\s+fd00::/8`),
+			},
+			{
+				Config:        testAccIpv6UlaResourceConfigEmpty(),
+				ResourceName:  "nanoid_ipv6_ula.test",
+				ImportState:   true,
+				ImportStateId: "fd12:3456:789a:1::/48",
+				ExpectError:   regexp.MustCompile(`must not have bits\s+set`),
+			},
+		},
+	})
+}
+
+func testAccIpv6UlaResourceConfig(subnetCount int) string {
+	return fmt.Sprintf(`
+resource "nanoid_ipv6_ula" "test" {
+  subnet_count = %d
+}
+`, subnetCount)
+}
+
+func testAccIpv6UlaResourceConfigEmpty() string {
+	return `resource "nanoid_ipv6_ula" "test" {}`
+}
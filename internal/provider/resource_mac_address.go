@@ -0,0 +1,374 @@
+// Copyright (c) The Nanoid Provider for Terraform Authors
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/mapplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	gonanoid "github.com/matoous/go-nanoid"
+)
+
+const MAC_FORMAT_COLON = "colon"
+const MAC_FORMAT_HYPHEN = "hyphen"
+const MAC_FORMAT_DOTTED = "dotted"
+
+const DEFAULT_MAC_POOL = "default"
+const DEFAULT_MAC_FORMAT = MAC_FORMAT_COLON
+
+// MAC_ATTEMPTS bounds the number of addresses generated to find one that is
+// not already allocated in the pool.
+const MAC_ATTEMPTS = 100
+
+var macPrefixPattern = regexp.MustCompile(`^[0-9a-fA-F]{2}([:-]?[0-9a-fA-F]{2}){0,4}$`)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ resource.Resource = &MacAddressResource{}
+var _ resource.ResourceWithImportState = &MacAddressResource{}
+
+func NewMacAddressResource() resource.Resource {
+	return &MacAddressResource{}
+}
+
+// MacAddressResource defines the resource implementation.
+type MacAddressResource struct {
+	providerData *NanoidProviderData
+}
+
+// MacAddressResourceModel describes the resource data model.
+type MacAddressResourceModel struct {
+	Id                     types.String  `tfsdk:"id"`
+	Pool                   types.String  `tfsdk:"pool"`
+	Prefix                 types.String  `tfsdk:"prefix"`
+	Format                 types.String  `tfsdk:"format"`
+	Keepers                types.Map     `tfsdk:"keepers"`
+	KeepersSensitive       types.Dynamic `tfsdk:"keepers_sensitive"`
+	KeepersSensitiveDigest types.String  `tfsdk:"keepers_sensitive_digest"`
+	Address                types.String  `tfsdk:"address"`
+}
+
+func (d *MacAddressResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_mac_address"
+}
+
+func (d *MacAddressResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "The mac_address resource generates unicast, locally administered MAC addresses: " +
+			"the least significant bit of the first octet is cleared and the second least significant bit is set.\n\n" +
+			"The address is unique among the `nanoid_mac_address` resources that share the same `pool` and are created during the same apply.",
+		Attributes: map[string]schema.Attribute{
+			"pool": schema.StringAttribute{
+				MarkdownDescription: fmt.Sprintf("The name of the pool the address must be unique in.\n"+
+					"The default value is `%q`.", DEFAULT_MAC_POOL),
+				Optional: true,
+				Computed: true,
+				Default:  stringdefault.StaticString(DEFAULT_MAC_POOL),
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+					stringplanmodifier.UseStateForUnknown(),
+				},
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+				},
+			},
+
+			"prefix": schema.StringAttribute{
+				MarkdownDescription: "The fixed leading octets of the address, for example `02:00:5e`.\n" +
+					"Should be between 1 and 5 octets, optionally separated by `:` or `-`.\n" +
+					"The first octet must be unicast and locally administered.",
+				Optional: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+				Validators: []validator.String{
+					stringvalidator.RegexMatches(macPrefixPattern, "must be between 1 and 5 hexadecimal octets, optionally separated by `:` or `-`"),
+				},
+			},
+
+			"format": schema.StringAttribute{
+				MarkdownDescription: fmt.Sprintf("The format of the address, one of `%s` (`02:00:5e:10:00:01`), "+
+					"`%s` (`02-00-5e-10-00-01`) or `%s` (`0200.5e10.0001`).\n"+
+					"The default value is `%q`.", MAC_FORMAT_COLON, MAC_FORMAT_HYPHEN, MAC_FORMAT_DOTTED, DEFAULT_MAC_FORMAT),
+				Optional: true,
+				Computed: true,
+				Default:  stringdefault.StaticString(DEFAULT_MAC_FORMAT),
+				Validators: []validator.String{
+					stringvalidator.OneOf(MAC_FORMAT_COLON, MAC_FORMAT_HYPHEN, MAC_FORMAT_DOTTED),
+				},
+			},
+
+			"keepers": schema.MapAttribute{
+				Description: "Arbitrary map of values that, when changed, will trigger recreation of " +
+					"resource. See [the main provider documentation](../index.html) for more information.",
+				ElementType: types.StringType,
+				Optional:    true,
+				PlanModifiers: []planmodifier.Map{
+					mapplanmodifier.RequiresReplaceIfConfigured(),
+				},
+			},
+
+			"keepers_sensitive": schema.DynamicAttribute{
+				MarkdownDescription: "Arbitrary sensitive values, such as credential versions, that, when changed, will trigger recreation of resource. " +
+					"The values are write-only: only their salted digest is stored, in `keepers_sensitive_digest`. Write-only attributes require Terraform 1.11 or later.",
+				Optional:  true,
+				Sensitive: true,
+				WriteOnly: true,
+			},
+
+			"keepers_sensitive_digest": schema.StringAttribute{
+				MarkdownDescription: "The salted SHA-256 digest of `keepers_sensitive`, of the form `sha256:<salt>:<digest>`, " +
+					"against which their configured values are compared.",
+				Computed: true,
+				PlanModifiers: []planmodifier.String{
+					keepersSensitiveDigestModifier{requiresReplace: true},
+				},
+			},
+
+			"address": schema.StringAttribute{
+				MarkdownDescription: "The generated address, in the configured format.",
+				Computed:            true,
+			},
+
+			"id": schema.StringAttribute{
+				MarkdownDescription: "The generated address, in the `colon` format.",
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+		},
+	}
+}
+
+func (d *MacAddressResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerData, ok := req.ProviderData.(*NanoidProviderData)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *provider.NanoidProviderData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	d.providerData = providerData
+}
+
+func (r *MacAddressResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data MacAddressResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	prefix, err := parseMacPrefix(data.Prefix.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Invalid prefix", fmt.Sprintf("Invalid prefix: %s.", err))
+		return
+	}
+
+	mac, err := r.providerData.reserveMac(data.Pool.ValueString(), prefix)
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to generate id", fmt.Sprintf("Failed to generate id: %s.", err))
+		return
+	}
+
+	data.Id = types.StringValue(formatMac(mac, MAC_FORMAT_COLON))
+	data.Address = types.StringValue(formatMac(mac, data.Format.ValueString()))
+	resp.Diagnostics.Append(setKeepersSensitiveDigest(ctx, req.Config, &data.KeepersSensitiveDigest)...)
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (d *MacAddressResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data MacAddressResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *MacAddressResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var data MacAddressResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Only the format can change in place, the address is rendered again.
+	mac, err := parseMac(data.Id.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Invalid id", fmt.Sprintf("Invalid id: %s.", err))
+		return
+	}
+
+	data.Address = types.StringValue(formatMac(mac, data.Format.ValueString()))
+	resp.Diagnostics.Append(setKeepersSensitiveDigest(ctx, req.Config, &data.KeepersSensitiveDigest)...)
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *MacAddressResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var data MacAddressResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	r.providerData.releaseMac(data.Pool.ValueString(), data.Id.ValueString())
+}
+
+func (r *MacAddressResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	mac, err := parseMac(req.ID)
+	if err != nil {
+		resp.Diagnostics.AddError("Invalid id", fmt.Sprintf("The id must be a MAC address: %s.", err))
+		return
+	}
+	if mac[0]&0x01 != 0 || mac[0]&0x02 == 0 {
+		resp.Diagnostics.AddError("Invalid id", "The id must be a unicast, locally administered MAC address.")
+		return
+	}
+
+	format := MAC_FORMAT_COLON
+	if strings.Contains(req.ID, "-") {
+		format = MAC_FORMAT_HYPHEN
+	} else if strings.Contains(req.ID, ".") {
+		format = MAC_FORMAT_DOTTED
+	}
+
+	state := &MacAddressResourceModel{
+		Id:                     types.StringValue(formatMac(mac, MAC_FORMAT_COLON)),
+		Pool:                   types.StringValue(DEFAULT_MAC_POOL),
+		Prefix:                 types.StringNull(),
+		Format:                 types.StringValue(format),
+		Keepers:                types.MapNull(types.StringType),
+		KeepersSensitive:       types.DynamicNull(),
+		KeepersSensitiveDigest: types.StringNull(),
+		Address:                types.StringValue(formatMac(mac, format)),
+	}
+
+	diags := resp.State.Set(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+// parseMacPrefix decodes the prefix octets and checks that they can start a
+// unicast, locally administered address.
+func parseMacPrefix(prefix string) ([]byte, error) {
+	if prefix == "" {
+		return nil, nil
+	}
+
+	octets, err := hex.DecodeString(strings.NewReplacer(":", "", "-", "").Replace(prefix))
+	if err != nil || len(octets) > 5 {
+		return nil, fmt.Errorf("the prefix %q must be between 1 and 5 hexadecimal octets", prefix)
+	}
+	if octets[0]&0x01 != 0 || octets[0]&0x02 == 0 {
+		return nil, fmt.Errorf("the first octet of the prefix %q must be unicast and locally administered, such as 02, 06, 0a or 0e", prefix)
+	}
+
+	return octets, nil
+}
+
+// parseMac decodes an address in any of the supported formats.
+func parseMac(address string) ([]byte, error) {
+	octets, err := hex.DecodeString(strings.NewReplacer(":", "", "-", "", ".", "").Replace(address))
+	if err != nil || len(octets) != 6 {
+		return nil, fmt.Errorf("%q is not a MAC address of 6 octets", address)
+	}
+
+	return octets, nil
+}
+
+func formatMac(mac []byte, format string) string {
+	s := hex.EncodeToString(mac)
+	switch format {
+	case MAC_FORMAT_HYPHEN:
+		return strings.Join([]string{s[0:2], s[2:4], s[4:6], s[6:8], s[8:10], s[10:12]}, "-")
+	case MAC_FORMAT_DOTTED:
+		return strings.Join([]string{s[0:4], s[4:8], s[8:12]}, ".")
+	default:
+		return strings.Join([]string{s[0:2], s[2:4], s[4:6], s[6:8], s[8:10], s[10:12]}, ":")
+	}
+}
+
+// generateMac generates a random unicast, locally administered address
+// starting with the prefix.
+func generateMac(prefix []byte) ([]byte, error) {
+	id, err := gonanoid.Generate(HEX_ALPHABET, 12)
+	if err != nil {
+		return nil, err
+	}
+
+	mac, err := hex.DecodeString(id)
+	if err != nil {
+		return nil, err
+	}
+
+	copy(mac, prefix)
+	mac[0] = (mac[0] | 0x02) &^ 0x01
+	return mac, nil
+}
+
+// reserveMac generates an address that is not already allocated in the pool,
+// and allocates it.
+func (p *NanoidProviderData) reserveMac(pool string, prefix []byte) ([]byte, error) {
+	if p == nil {
+		return nil, fmt.Errorf("the provider has not been configured")
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	allocated := p.macs[pool]
+	if allocated == nil {
+		allocated = make(map[string]bool)
+		p.macs[pool] = allocated
+	}
+
+	for i := 0; i < MAC_ATTEMPTS; i++ {
+		mac, err := generateMac(prefix)
+		if err != nil {
+			return nil, err
+		}
+
+		id := formatMac(mac, MAC_FORMAT_COLON)
+		if !allocated[id] {
+			allocated[id] = true
+			return mac, nil
+		}
+	}
+
+	return nil, fmt.Errorf("no address is available in the pool %q after %d attempts", pool, MAC_ATTEMPTS)
+}
+
+func (p *NanoidProviderData) releaseMac(pool string, id string) {
+	if p == nil {
+		return
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	delete(p.macs[pool], id)
+}
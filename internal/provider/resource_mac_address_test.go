@@ -0,0 +1,135 @@
+// Copyright (c) The Nanoid Provider for Terraform Authors
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+)
+
+func testCheckMacBits(input string) error {
+	mac, err := parseMac(input)
+	if err != nil {
+		return err
+	}
+	if mac[0]&0x01 != 0 || mac[0]&0x02 == 0 {
+		return fmt.Errorf("expected %q to be unicast and locally administered", input)
+	}
+
+	return nil
+}
+
+func TestAccMacAddressResource(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccMacAddressResourceConfigEmpty(),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("nanoid_mac_address.test", "format", "colon"),
+					resource.TestMatchResourceAttr("nanoid_mac_address.test", "address", regexp.MustCompile(`^([0-9a-f]{2}:){5}[0-9a-f]{2}$`)),
+					resource.TestCheckResourceAttrWith("nanoid_mac_address.test", "address", testCheckMacBits),
+					resource.TestCheckResourceAttrPair("nanoid_mac_address.test", "id", "nanoid_mac_address.test", "address"),
+				),
+			},
+			{
+				ResourceName:      "nanoid_mac_address.test",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func TestAccMacAddressResource_WithPrefixAndFormat(t *testing.T) {
+	var id string
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccMacAddressResourceConfig("0a:00:5e", "hyphen"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestMatchResourceAttr("nanoid_mac_address.test", "address", regexp.MustCompile(`^0a-00-5e(-[0-9a-f]{2}){3}$`)),
+					resource.TestCheckResourceAttrWith("nanoid_mac_address.test", "id", func(input string) error {
+						id = input
+						return nil
+					}),
+				),
+			},
+			{
+				Config: testAccMacAddressResourceConfig("0a:00:5e", "dotted"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestMatchResourceAttr("nanoid_mac_address.test", "address", regexp.MustCompile(`^0a00\.5e[0-9a-f]{2}\.[0-9a-f]{4}$`)),
+					resource.TestCheckResourceAttrWith("nanoid_mac_address.test", "id", func(input string) error {
+						if input != id {
+							return fmt.Errorf("expected the address %q to be kept, got %q", id, input)
+						}
+						return nil
+					}),
+				),
+			},
+		},
+	})
+}
+
+func TestAccMacAddressResource_InvalidPrefix(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      testAccMacAddressResourceConfig("01:00:5e", "colon"),
+				ExpectError: regexp.MustCompile(`must\s+be\s+unicast\s+and\s+locally\s+administered`),
+			},
+		},
+	})
+}
+
+func TestAccMacAddressResource_UniqueInPool(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+resource "nanoid_mac_address" "test" {
+  count  = 16
+  prefix = "02:00:00:00:00"
+}
+`,
+				Check: func(s *terraform.State) error {
+					seen := map[string]bool{}
+					for i := 0; i < 16; i++ {
+						address := s.RootModule().Resources[fmt.Sprintf("nanoid_mac_address.test.%d", i)].Primary.Attributes["address"]
+						if seen[address] {
+							return fmt.Errorf("the address %s was generated more than once", address)
+						}
+						seen[address] = true
+					}
+
+					return nil
+				},
+			},
+		},
+	})
+}
+
+func testAccMacAddressResourceConfig(prefix string, format string) string {
+	return fmt.Sprintf(`
+resource "nanoid_mac_address" "test" {
+  prefix = %q
+  format = %q
+}
+`, prefix, format)
+}
+
+func testAccMacAddressResourceConfigEmpty() string {
+	return `resource "nanoid_mac_address" "test" {}`
+}
@@ -0,0 +1,340 @@
+// Copyright (c) The Nanoid Provider for Terraform Authors
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64default"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/mapplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ resource.Resource = &PairResource{}
+var _ resource.ResourceWithImportState = &PairResource{}
+var _ resource.ResourceWithModifyPlan = &PairResource{}
+
+func NewPairResource() resource.Resource {
+	return &PairResource{}
+}
+
+// PairResource defines the resource implementation.
+type PairResource struct {
+	providerData *NanoidProviderData
+}
+
+// PairResourceModel describes the resource data model.
+type PairResourceModel struct {
+	Id                     types.String  `tfsdk:"id"`
+	Alphabet               types.String  `tfsdk:"alphabet"`
+	Length                 types.Int64   `tfsdk:"length"`
+	Flip                   types.String  `tfsdk:"flip"`
+	Keepers                types.Map     `tfsdk:"keepers"`
+	KeepersSensitive       types.Dynamic `tfsdk:"keepers_sensitive"`
+	KeepersSensitiveDigest types.String  `tfsdk:"keepers_sensitive_digest"`
+	Active                 types.String  `tfsdk:"active"`
+	Standby                types.String  `tfsdk:"standby"`
+}
+
+func (d *PairResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_pair"
+}
+
+func (d *PairResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "The pair resource holds two ids for blue-green naming, an `active` id in use and a `standby` id to cut over to.\n\n" +
+			"Unlike the `create_before_destroy` pattern of the id resource, which replaces the resource, changing `flip` swaps the roles in place: " +
+			"the standby id becomes active and a new standby id is generated, while the retired active id is discarded.\n\n" +
+			"Existing pairs can be imported with the active and standby ids separated by a comma, for example `active,standby`, " +
+			"followed by the configured value of `flip`, if any, so that importing does not flip the pair, for example `active,standby,blue`.",
+		Attributes: map[string]schema.Attribute{
+			"alphabet": schema.StringAttribute{
+				MarkdownDescription: fmt.Sprintf("Supply your own list of characters to use for id generation.\n"+
+					"Should be between 1 and %d characters long, with the same checks as the `alphabet` of the id resource.\n"+
+					"The default value is `%s`.", MAX_ID_ALPHABET_LENGTH, DEFAULT_ID_ALPHABET),
+				Optional: true,
+				Computed: true,
+				Default:  stringdefault.StaticString(DEFAULT_ID_ALPHABET),
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+				Validators: []validator.String{
+					stringvalidator.UTF8LengthBetween(1, MAX_ID_ALPHABET_LENGTH),
+					validAlphabet(),
+				},
+			},
+
+			"length": schema.Int64Attribute{
+				MarkdownDescription: fmt.Sprintf("The length of both ids.\n"+
+					"Should be between 1 and the `max_id_length` of the provider, %d by default.\n"+
+					"The default value is %d.", DEFAULT_MAX_ID_LENGTH, DEFAULT_ID_LENGTH),
+				Optional: true,
+				Computed: true,
+				Default:  int64default.StaticInt64(DEFAULT_ID_LENGTH),
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.RequiresReplace(),
+				},
+				Validators: []validator.Int64{
+					int64validator.AtLeast(1),
+				},
+			},
+
+			"flip": schema.StringAttribute{
+				MarkdownDescription: "An arbitrary value that, when changed, makes the standby id active and generates a new standby id, in place.",
+				Optional:            true,
+			},
+
+			"keepers": schema.MapAttribute{
+				Description: "Arbitrary map of values that, when changed, will trigger recreation of " +
+					"resource. See [the main provider documentation](../index.html) for more information.",
+				ElementType: types.StringType,
+				Optional:    true,
+				PlanModifiers: []planmodifier.Map{
+					mapplanmodifier.RequiresReplaceIfConfigured(),
+				},
+			},
+
+			"keepers_sensitive": schema.DynamicAttribute{
+				MarkdownDescription: "Arbitrary sensitive values, such as credential versions, that, when changed, will trigger recreation of resource. " +
+					"The values are write-only: only their salted digest is stored, in `keepers_sensitive_digest`. Write-only attributes require Terraform 1.11 or later.",
+				Optional:  true,
+				Sensitive: true,
+				WriteOnly: true,
+			},
+
+			"keepers_sensitive_digest": schema.StringAttribute{
+				MarkdownDescription: "The salted SHA-256 digest of `keepers_sensitive`, of the form `sha256:<salt>:<digest>`, " +
+					"against which their configured values are compared.",
+				Computed: true,
+				PlanModifiers: []planmodifier.String{
+					keepersSensitiveDigestModifier{requiresReplace: true},
+				},
+			},
+
+			"active": schema.StringAttribute{
+				MarkdownDescription: "The id in use.",
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+
+			"standby": schema.StringAttribute{
+				MarkdownDescription: "The id to cut over to, which becomes active when `flip` changes.",
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+
+			"id": schema.StringAttribute{
+				MarkdownDescription: "The active id.",
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+		},
+	}
+}
+
+func (d *PairResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerData, ok := req.ProviderData.(*NanoidProviderData)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *provider.NanoidProviderData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	d.providerData = providerData
+}
+
+// maxIdLength returns the maximum length of the ids, configured on the provider.
+func (r *PairResource) maxIdLength() int64 {
+	if r.providerData == nil {
+		return DEFAULT_MAX_ID_LENGTH
+	}
+
+	return r.providerData.maxIdLength
+}
+
+func (r *PairResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+
+	var plan PairResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Only the ids of new resources are checked, so that lowering
+	// max_id_length does not affect existing pairs.
+	if req.State.Raw.IsNull() {
+		if maxIdLength := r.maxIdLength(); !plan.Length.IsUnknown() && plan.Length.ValueInt64() > maxIdLength {
+			resp.Diagnostics.AddAttributeError(path.Root("length"), "Invalid Attribute Value",
+				fmt.Sprintf("Attribute length must be at most %d, the max_id_length of the provider, got: %d", maxIdLength, plan.Length.ValueInt64()))
+		}
+		return
+	}
+
+	var state PairResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	switch {
+	case plan.Flip.IsUnknown():
+		// Whether the roles are swapped is only known when applying.
+		plan.Active = types.StringUnknown()
+		plan.Standby = types.StringUnknown()
+		plan.Id = types.StringUnknown()
+	case !plan.Flip.Equal(state.Flip):
+		plan.Active = state.Standby
+		plan.Standby = types.StringUnknown()
+		plan.Id = state.Standby
+	default:
+		return
+	}
+
+	resp.Diagnostics.Append(resp.Plan.Set(ctx, &plan)...)
+}
+
+func (r *PairResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data PairResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	active, diags := data.generate()
+	resp.Diagnostics.Append(diags...)
+	standby, diags := data.generate()
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	data.Active = types.StringValue(active)
+	data.Standby = types.StringValue(standby)
+	data.Id = data.Active
+	resp.Diagnostics.Append(setKeepersSensitiveDigest(ctx, req.Config, &data.KeepersSensitiveDigest)...)
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (d *PairResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data PairResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *PairResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var data, state PairResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	data.Active = state.Active
+	data.Standby = state.Standby
+	if !data.Flip.Equal(state.Flip) {
+		// The standby id becomes active and only the retired slot is regenerated.
+		standby, diags := data.generate()
+		resp.Diagnostics.Append(diags...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		data.Active = state.Standby
+		data.Standby = types.StringValue(standby)
+	}
+	data.Id = data.Active
+
+	resp.Diagnostics.Append(setKeepersSensitiveDigest(ctx, req.Config, &data.KeepersSensitiveDigest)...)
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *PairResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var data PairResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+func (r *PairResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	parts := strings.SplitN(req.ID, ",", 3)
+	length := utf8.RuneCountInString(parts[0])
+	maxIdLength := r.maxIdLength()
+	if len(parts) < 2 || length < 1 || int64(length) > maxIdLength || utf8.RuneCountInString(parts[1]) != length {
+		resp.Diagnostics.AddError("Invalid id",
+			fmt.Sprintf("The id must be the active and standby ids, of the same length of at most %d characters, "+
+				"and optionally the value of flip, separated by commas.", maxIdLength))
+		return
+	}
+	active, standby := parts[0], parts[1]
+	flip := types.StringNull()
+	if len(parts) == 3 {
+		flip = types.StringValue(parts[2])
+	}
+
+	state := &PairResourceModel{
+		Id:                     types.StringValue(active),
+		Alphabet:               types.StringValue(DEFAULT_ID_ALPHABET),
+		Length:                 types.Int64Value(int64(length)),
+		Flip:                   flip,
+		Keepers:                types.MapNull(types.StringType),
+		KeepersSensitive:       types.DynamicNull(),
+		KeepersSensitiveDigest: types.StringNull(),
+		Active:                 types.StringValue(active),
+		Standby:                types.StringValue(standby),
+	}
+
+	diags := resp.State.Set(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+// generate returns a new id of the pair.
+func (data *PairResourceModel) generate() (string, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	positions := &positionalAlphabets{alphabet: []rune(data.Alphabet.ValueString())}
+	id, err := positions.generate(int(data.Length.ValueInt64()))
+	if err != nil {
+		diags.AddAttributeError(path.Root("alphabet"), "Failed to generate id", fmt.Sprintf("Failed to generate id: %s.", err))
+	}
+
+	return id, diags
+}
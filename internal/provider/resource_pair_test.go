@@ -0,0 +1,119 @@
+// Copyright (c) The Nanoid Provider for Terraform Authors
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+)
+
+func TestAccPairResource(t *testing.T) {
+	var active, standby string
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccPairResourceConfig("blue"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttrWith("nanoid_pair.test", "active", testCheckLen(21)),
+					resource.TestCheckResourceAttrWith("nanoid_pair.test", "standby", testCheckLen(21)),
+					resource.TestCheckResourceAttrPair("nanoid_pair.test", "id", "nanoid_pair.test", "active"),
+					testExtractResourceAttr("nanoid_pair.test", "active", &active),
+					testExtractResourceAttr("nanoid_pair.test", "standby", &standby),
+				),
+			},
+			{
+				Config: testAccPairResourceConfig("green"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttrPtr("nanoid_pair.test", "active", &standby),
+					testCheckResourceAttrChanged("nanoid_pair.test", "standby", &standby),
+					testCheckResourceAttrChanged("nanoid_pair.test", "standby", &active),
+					resource.TestCheckResourceAttrPair("nanoid_pair.test", "id", "nanoid_pair.test", "active"),
+				),
+			},
+			{
+				Config:   testAccPairResourceConfig("green"),
+				PlanOnly: true,
+			},
+			{
+				ResourceName:      "nanoid_pair.test",
+				ImportState:       true,
+				ImportStateIdFunc: testAccPairResourceImportStateId("nanoid_pair.test"),
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func TestAccPairResource_WithProviderMaxIdLength(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      testAccPairResourceConfigLength(65),
+				ExpectError: regexp.MustCompile(`Attribute\s+length\s+must\s+be\s+at\s+most\s+64,\s+the\s+max_id_length\s+of\s+the\s+provider`),
+			},
+			{
+				Config: testAccIdResourceConfigMaxIdLength(128) + testAccPairResourceConfigLength(100),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttrWith("nanoid_pair.test", "active", testCheckLen(100)),
+					resource.TestCheckResourceAttrWith("nanoid_pair.test", "standby", testCheckLen(100)),
+				),
+			},
+			{
+				Config:            testAccIdResourceConfigMaxIdLength(128) + testAccPairResourceConfigLength(100),
+				ResourceName:      "nanoid_pair.test",
+				ImportState:       true,
+				ImportStateIdFunc: testAccPairResourceImportStateId("nanoid_pair.test"),
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func TestAccPairResource_InvalidImport(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:        `resource "nanoid_pair" "test" {}`,
+				ResourceName:  "nanoid_pair.test",
+				ImportState:   true,
+				ImportStateId: "abc,de",
+				ExpectError:   regexp.MustCompile(`The\s+id\s+must\s+be\s+the\s+active\s+and\s+standby\s+ids`),
+			},
+		},
+	})
+}
+
+func testAccPairResourceImportStateId(name string) resource.ImportStateIdFunc {
+	return func(s *terraform.State) (string, error) {
+		attributes := s.RootModule().Resources[name].Primary.Attributes
+		return fmt.Sprintf("%s,%s,%s", attributes["active"], attributes["standby"], attributes["flip"]), nil
+	}
+}
+
+func testAccPairResourceConfig(flip string) string {
+	return fmt.Sprintf(`
+resource "nanoid_pair" "test" {
+  flip = %q
+}
+`, flip)
+}
+
+func testAccPairResourceConfigLength(length int) string {
+	return fmt.Sprintf(`
+resource "nanoid_pair" "test" {
+  length = %d
+  flip   = "blue"
+}
+`, length)
+}
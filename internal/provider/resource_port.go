@@ -0,0 +1,403 @@
+// Copyright (c) The Nanoid Provider for Terraform Authors
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"crypto/rand"
+	"fmt"
+	"math/big"
+	"strconv"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/setvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/boolplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64default"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/mapplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/setplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+const DEFAULT_PORT_POOL = "default"
+const DEFAULT_PORT_MIN = 1024
+const DEFAULT_PORT_MAX = 65535
+
+// WELL_KNOWN_PORT_MAX is the last port of the IANA system port range.
+const WELL_KNOWN_PORT_MAX = 1023
+
+// RESERVED_PORTS are the ports above the system ports that the IANA Service
+// Name and Transport Protocol Port Number Registry lists as "Reserved": the
+// first user port and the last user port before the dynamic range.
+var RESERVED_PORTS = []int64{1024, 49151}
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ resource.Resource = &PortResource{}
+var _ resource.ResourceWithImportState = &PortResource{}
+
+func NewPortResource() resource.Resource {
+	return &PortResource{}
+}
+
+// PortResource defines the resource implementation.
+type PortResource struct {
+	providerData *NanoidProviderData
+}
+
+// PortResourceModel describes the resource data model.
+type PortResourceModel struct {
+	Id                     types.String  `tfsdk:"id"`
+	Pool                   types.String  `tfsdk:"pool"`
+	Min                    types.Int64   `tfsdk:"min"`
+	Max                    types.Int64   `tfsdk:"max"`
+	Exclude                types.Set     `tfsdk:"exclude"`
+	ExcludeWellKnown       types.Bool    `tfsdk:"exclude_well_known"`
+	Record                 types.Bool    `tfsdk:"record"`
+	Keepers                types.Map     `tfsdk:"keepers"`
+	KeepersSensitive       types.Dynamic `tfsdk:"keepers_sensitive"`
+	KeepersSensitiveDigest types.String  `tfsdk:"keepers_sensitive_digest"`
+	Port                   types.Int64   `tfsdk:"port"`
+}
+
+func (d *PortResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_port"
+}
+
+func (d *PortResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "The port resource picks a random port from a range.\n\n" +
+			fmt.Sprintf("The ports reserved by IANA (`%v`) and the excluded ports are never picked. ", RESERVED_PORTS) +
+			"The port is unique among the `nanoid_port` resources that share the same `pool` and are known to the same provider process: " +
+			"the ports picked, read or imported by the provider are recorded in a ledger of the pool that later picks avoid, unless `record` is `false`. " +
+			"Terraform starts a new provider process for each plan and apply, so the ports of resources that are left untouched by an apply " +
+			"are not in its ledger: exclude them with `exclude` where they must not be picked again.",
+		Attributes: map[string]schema.Attribute{
+			"pool": schema.StringAttribute{
+				MarkdownDescription: fmt.Sprintf("The name of the pool the port must be unique in.\n"+
+					"The default value is `%q`.", DEFAULT_PORT_POOL),
+				Optional: true,
+				Computed: true,
+				Default:  stringdefault.StaticString(DEFAULT_PORT_POOL),
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+					stringplanmodifier.UseStateForUnknown(),
+				},
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+				},
+			},
+
+			"min": schema.Int64Attribute{
+				MarkdownDescription: fmt.Sprintf("The lowest port that can be picked.\nShould be between 1 and 65535.\nThe default value is %d.", DEFAULT_PORT_MIN),
+				Optional:            true,
+				Computed:            true,
+				Default:             int64default.StaticInt64(DEFAULT_PORT_MIN),
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.RequiresReplace(),
+					int64planmodifier.UseStateForUnknown(),
+				},
+				Validators: []validator.Int64{
+					int64validator.Between(1, 65535),
+				},
+			},
+
+			"max": schema.Int64Attribute{
+				MarkdownDescription: fmt.Sprintf("The highest port that can be picked.\nShould be between 1 and 65535.\nThe default value is %d.", DEFAULT_PORT_MAX),
+				Optional:            true,
+				Computed:            true,
+				Default:             int64default.StaticInt64(DEFAULT_PORT_MAX),
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.RequiresReplace(),
+					int64planmodifier.UseStateForUnknown(),
+				},
+				Validators: []validator.Int64{
+					int64validator.Between(1, 65535),
+					int64validator.AtLeastSumOf(path.MatchRoot("min")),
+				},
+			},
+
+			"exclude": schema.SetAttribute{
+				MarkdownDescription: "The ports that must never be picked.",
+				ElementType:         types.Int64Type,
+				Optional:            true,
+				PlanModifiers: []planmodifier.Set{
+					setplanmodifier.RequiresReplace(),
+				},
+				Validators: []validator.Set{
+					setvalidator.ValueInt64sAre(int64validator.Between(1, 65535)),
+				},
+			},
+
+			"exclude_well_known": schema.BoolAttribute{
+				MarkdownDescription: fmt.Sprintf("Whether the well-known system ports, up to %d, must never be picked.\n"+
+					"The default value is `true`.", WELL_KNOWN_PORT_MAX),
+				Optional: true,
+				Computed: true,
+				Default:  booldefault.StaticBool(true),
+				PlanModifiers: []planmodifier.Bool{
+					boolplanmodifier.RequiresReplace(),
+					boolplanmodifier.UseStateForUnknown(),
+				},
+			},
+
+			"record": schema.BoolAttribute{
+				MarkdownDescription: "Whether the port is recorded in the ledger of the pool, so that the other `nanoid_port` resources of the pool never pick it. " +
+					"A port that is not recorded still avoids the recorded ones, but may be picked again by other resources.\n" +
+					"The default value is `true`.",
+				Optional: true,
+				Computed: true,
+				Default:  booldefault.StaticBool(true),
+			},
+
+			"keepers": schema.MapAttribute{
+				Description: "Arbitrary map of values that, when changed, will trigger recreation of " +
+					"resource. See [the main provider documentation](../index.html) for more information.",
+				ElementType: types.StringType,
+				Optional:    true,
+				PlanModifiers: []planmodifier.Map{
+					mapplanmodifier.RequiresReplaceIfConfigured(),
+				},
+			},
+
+			"keepers_sensitive": schema.DynamicAttribute{
+				MarkdownDescription: "Arbitrary sensitive values, such as credential versions, that, when changed, will trigger recreation of resource. " +
+					"The values are write-only: only their salted digest is stored, in `keepers_sensitive_digest`. Write-only attributes require Terraform 1.11 or later.",
+				Optional:  true,
+				Sensitive: true,
+				WriteOnly: true,
+			},
+
+			"keepers_sensitive_digest": schema.StringAttribute{
+				MarkdownDescription: "The salted SHA-256 digest of `keepers_sensitive`, of the form `sha256:<salt>:<digest>`, " +
+					"against which their configured values are compared.",
+				Computed: true,
+				PlanModifiers: []planmodifier.String{
+					keepersSensitiveDigestModifier{requiresReplace: true},
+				},
+			},
+
+			"port": schema.Int64Attribute{
+				MarkdownDescription: "The picked port.",
+				Computed:            true,
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.UseStateForUnknown(),
+				},
+			},
+
+			"id": schema.StringAttribute{
+				MarkdownDescription: "The picked port, as a string.",
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+		},
+	}
+}
+
+func (d *PortResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerData, ok := req.ProviderData.(*NanoidProviderData)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *provider.NanoidProviderData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	d.providerData = providerData
+}
+
+func (r *PortResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data PortResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var exclude []int64
+	resp.Diagnostics.Append(data.Exclude.ElementsAs(ctx, &exclude, false)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	excluded := make(map[int64]bool, len(exclude)+len(RESERVED_PORTS))
+	for _, port := range append(exclude, RESERVED_PORTS...) {
+		excluded[port] = true
+	}
+
+	minPort := data.Min.ValueInt64()
+	if data.ExcludeWellKnown.ValueBool() && minPort <= WELL_KNOWN_PORT_MAX {
+		minPort = WELL_KNOWN_PORT_MAX + 1
+	}
+
+	port, err := r.providerData.reservePort(data.Pool.ValueString(), minPort, data.Max.ValueInt64(), excluded, data.Record.ValueBool())
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to pick port", fmt.Sprintf("Failed to pick port: %s.", err))
+		return
+	}
+
+	data.Port = types.Int64Value(port)
+	data.Id = types.StringValue(strconv.FormatInt(port, 10))
+	resp.Diagnostics.Append(setKeepersSensitiveDigest(ctx, req.Config, &data.KeepersSensitiveDigest)...)
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (d *PortResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data PortResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if data.Record.ValueBool() {
+		d.providerData.recordPort(data.Pool.ValueString(), data.Port.ValueInt64())
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *PortResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var data PortResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	// Only the keepers, when removed, and record change in place.
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("keepers"), &data.Keepers)...)
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("record"), &data.Record)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if data.Record.ValueBool() {
+		r.providerData.recordPort(data.Pool.ValueString(), data.Port.ValueInt64())
+	}
+	resp.Diagnostics.Append(setKeepersSensitiveDigest(ctx, req.Config, &data.KeepersSensitiveDigest)...)
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *PortResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var data PortResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// A port that is not recorded may be held by a resource that is.
+	if data.Record.ValueBool() {
+		r.providerData.releasePort(data.Pool.ValueString(), data.Port.ValueInt64())
+	}
+}
+
+func (r *PortResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	port, err := strconv.ParseInt(req.ID, 10, 64)
+	if err != nil || port < 1 || port > 65535 {
+		resp.Diagnostics.AddError("Invalid id", "The id must be a port between 1 and 65535.")
+		return
+	}
+
+	minPort := int64(DEFAULT_PORT_MIN)
+	if port < minPort {
+		minPort = port
+	}
+
+	state := &PortResourceModel{
+		Id:                     types.StringValue(req.ID),
+		Pool:                   types.StringValue(DEFAULT_PORT_POOL),
+		Min:                    types.Int64Value(minPort),
+		Max:                    types.Int64Value(DEFAULT_PORT_MAX),
+		Exclude:                types.SetNull(types.Int64Type),
+		ExcludeWellKnown:       types.BoolValue(port > WELL_KNOWN_PORT_MAX),
+		Record:                 types.BoolValue(true),
+		Keepers:                types.MapNull(types.StringType),
+		KeepersSensitive:       types.DynamicNull(),
+		KeepersSensitiveDigest: types.StringNull(),
+		Port:                   types.Int64Value(port),
+	}
+	r.providerData.recordPort(DEFAULT_PORT_POOL, port)
+
+	diags := resp.State.Set(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+// reservePort picks a random port between minPort and maxPort that is neither
+// excluded nor already allocated in the pool, and allocates it when record is
+// set.
+func (p *NanoidProviderData) reservePort(pool string, minPort int64, maxPort int64, excluded map[int64]bool, record bool) (int64, error) {
+	if p == nil {
+		return 0, fmt.Errorf("the provider has not been configured")
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	allocated := p.ports[pool]
+	var candidates []int64
+	for port := minPort; port <= maxPort; port++ {
+		if !excluded[port] && !allocated[port] {
+			candidates = append(candidates, port)
+		}
+	}
+	if len(candidates) == 0 {
+		return 0, fmt.Errorf("no port is available between %d and %d in the pool %q", minPort, maxPort, pool)
+	}
+
+	i, err := rand.Int(rand.Reader, big.NewInt(int64(len(candidates))))
+	if err != nil {
+		return 0, err
+	}
+
+	port := candidates[i.Int64()]
+	if !record {
+		return port, nil
+	}
+	if allocated == nil {
+		allocated = make(map[int64]bool)
+		p.ports[pool] = allocated
+	}
+	allocated[port] = true
+	return port, nil
+}
+
+// recordPort records a port of the state in the ledger of the pool.
+func (p *NanoidProviderData) recordPort(pool string, port int64) {
+	if p == nil {
+		return
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.ports[pool] == nil {
+		p.ports[pool] = make(map[int64]bool)
+	}
+	p.ports[pool][port] = true
+}
+
+func (p *NanoidProviderData) releasePort(pool string, port int64) {
+	if p == nil {
+		return
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	delete(p.ports[pool], port)
+}
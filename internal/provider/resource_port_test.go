@@ -0,0 +1,161 @@
+// Copyright (c) The Nanoid Provider for Terraform Authors
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+)
+
+func TestAccPortResource(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccPortResourceConfigEmpty(),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("nanoid_port.test", "pool", "default"),
+					resource.TestCheckResourceAttr("nanoid_port.test", "min", "1024"),
+					resource.TestCheckResourceAttr("nanoid_port.test", "max", "65535"),
+					resource.TestCheckResourceAttr("nanoid_port.test", "record", "true"),
+					resource.TestCheckResourceAttrPair("nanoid_port.test", "id", "nanoid_port.test", "port"),
+				),
+			},
+			{
+				ResourceName:      "nanoid_port.test",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func TestAccPortResource_UniqueInPool(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccPortResourceConfig(6, 20000, 20006, "20003"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					testCheckUniquePorts(6, "20003"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccPortResource_NotRecorded(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+resource "nanoid_port" "test" {
+  count  = 2
+  pool   = "test"
+  min    = 20000
+  max    = 20000
+  record = false
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("nanoid_port.test.0", "record", "false"),
+					resource.TestCheckResourceAttr("nanoid_port.test.0", "port", "20000"),
+					resource.TestCheckResourceAttr("nanoid_port.test.1", "port", "20000"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccPortResource_PoolExhausted(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      testAccPortResourceConfig(4, 20000, 20003, "20003"),
+				ExpectError: regexp.MustCompile(`no port is available`),
+			},
+		},
+	})
+}
+
+func TestPortLedger(t *testing.T) {
+	data := &NanoidProviderData{ports: make(map[string]map[int64]bool)}
+	data.recordPort("default", 20001)
+
+	port, err := data.reservePort("default", 20000, 20001, nil, true)
+	if err != nil || port != 20000 {
+		t.Fatalf("expected the port 20000, got %d, %v", port, err)
+	}
+	if _, err := data.reservePort("default", 20000, 20001, nil, true); err == nil {
+		t.Fatalf("expected the pool to be exhausted")
+	}
+
+	port, err = data.reservePort("other", 20001, 20001, nil, true)
+	if err != nil || port != 20001 {
+		t.Fatalf("expected the port 20001 in another pool, got %d, %v", port, err)
+	}
+
+	port, err = data.reservePort("other", 20000, 20001, nil, false)
+	if err != nil || port != 20000 {
+		t.Fatalf("expected the port 20000 in another pool, got %d, %v", port, err)
+	}
+	port, err = data.reservePort("other", 20000, 20001, nil, true)
+	if err != nil || port != 20000 {
+		t.Fatalf("expected the port 20000, not recorded, to be picked again, got %d, %v", port, err)
+	}
+
+	data.releasePort("default", 20001)
+	port, err = data.reservePort("default", 20000, 20001, nil, true)
+	if err != nil || port != 20001 {
+		t.Fatalf("expected the released port 20001, got %d, %v", port, err)
+	}
+}
+
+func testCheckUniquePorts(count int, excluded string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		seen := map[string]bool{}
+		for i := 0; i < count; i++ {
+			rs, ok := s.RootModule().Resources[fmt.Sprintf("nanoid_port.test.%d", i)]
+			if !ok {
+				return fmt.Errorf("nanoid_port.test.%d not found", i)
+			}
+			port := rs.Primary.Attributes["port"]
+			if port == excluded {
+				return fmt.Errorf("the excluded port %s was picked", port)
+			}
+			if seen[port] {
+				return fmt.Errorf("the port %s was picked more than once", port)
+			}
+			seen[port] = true
+		}
+
+		return nil
+	}
+}
+
+func testAccPortResourceConfig(count int, minPort int, maxPort int, exclude string) string {
+	return fmt.Sprintf(`
+resource "nanoid_port" "test" {
+  count   = %d
+  pool    = "test"
+  min     = %d
+  max     = %d
+  exclude = [%s]
+}
+`, count, minPort, maxPort, exclude)
+}
+
+func testAccPortResourceConfigEmpty() string {
+	return `resource "nanoid_port" "test" {}`
+}
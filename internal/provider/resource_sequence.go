@@ -0,0 +1,501 @@
+// Copyright (c) The Nanoid Provider for Terraform Authors
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64default"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	gonanoid "github.com/matoous/go-nanoid"
+)
+
+const DEFAULT_SEQUENCE_POOL = "default"
+const DEFAULT_SEQUENCE_FORMAT = "{seq}"
+const DEFAULT_SEQUENCE_START = 1
+const DEFAULT_SEQUENCE_RAND_LENGTH = 4
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ resource.Resource = &SequenceResource{}
+var _ resource.ResourceWithModifyPlan = &SequenceResource{}
+
+func NewSequenceResource() resource.Resource {
+	return &SequenceResource{}
+}
+
+// SequenceResource defines the resource implementation.
+type SequenceResource struct {
+	providerData *NanoidProviderData
+}
+
+// SequenceResourceModel describes the resource data model.
+type SequenceResourceModel struct {
+	Id                     types.String  `tfsdk:"id"`
+	Pool                   types.String  `tfsdk:"pool"`
+	Format                 types.String  `tfsdk:"format"`
+	Start                  types.Int64   `tfsdk:"start"`
+	Alphabet               types.String  `tfsdk:"alphabet"`
+	Keys                   types.Set     `tfsdk:"keys"`
+	Keepers                types.Map     `tfsdk:"keepers"`
+	KeepersSensitive       types.Dynamic `tfsdk:"keepers_sensitive"`
+	KeepersSensitiveDigest types.String  `tfsdk:"keepers_sensitive_digest"`
+	Numbers                types.Map     `tfsdk:"numbers"`
+	Values                 types.Map     `tfsdk:"values"`
+	HighWaterMark          types.Int64   `tfsdk:"high_water_mark"`
+}
+
+func (d *SequenceResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_sequence"
+}
+
+func (d *SequenceResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "The sequence resource hands out monotonically increasing numbers, rendered with a format such as `env-{seq:04}-{rand:4}`, " +
+			"to each of its `keys`.\n\n" +
+			"Adding a key assigns it the next number of the sequence in place, and removing a key never releases its number: " +
+			"the highest number handed out is kept in state as `high_water_mark` so values are never reused. " +
+			"Changing the `keepers` hands out new numbers to every key, continuing the sequence, while removing them keeps the numbers.\n\n" +
+			"The numbers are also unique among the `nanoid_sequence` resources that share the same `pool` and are handled by the same provider process, " +
+			"that is during the same apply, including a sequence that replaces a destroyed one. " +
+			"Sequences created by previous applies are not known to the provider: set `start` above their `high_water_mark` to continue them.",
+		Attributes: map[string]schema.Attribute{
+			"pool": schema.StringAttribute{
+				MarkdownDescription: fmt.Sprintf("The name of the pool the numbers must be unique in.\n"+
+					"The default value is `%q`.", DEFAULT_SEQUENCE_POOL),
+				Optional: true,
+				Computed: true,
+				Default:  stringdefault.StaticString(DEFAULT_SEQUENCE_POOL),
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+					stringplanmodifier.UseStateForUnknown(),
+				},
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+				},
+			},
+
+			"format": schema.StringAttribute{
+				MarkdownDescription: fmt.Sprintf("The template used to render the values.\n"+
+					"Supports the `{seq}` placeholder for the sequence number, `{seq:N}` for the sequence number zero-padded to N digits, "+
+					"`{rand}` and `{rand:N}` for a random string of %d or N characters from the alphabet, and `{key}` for the key.\n"+
+					"The default value is `%q`.", DEFAULT_SEQUENCE_RAND_LENGTH, DEFAULT_SEQUENCE_FORMAT),
+				Optional: true,
+				Computed: true,
+				Default:  stringdefault.StaticString(DEFAULT_SEQUENCE_FORMAT),
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+					stringplanmodifier.UseStateForUnknown(),
+				},
+				Validators: []validator.String{
+					stringvalidator.LengthBetween(1, 255),
+				},
+			},
+
+			"start": schema.Int64Attribute{
+				MarkdownDescription: fmt.Sprintf("The first number of the sequence.\nShould be at least 0.\nThe default value is %d.", DEFAULT_SEQUENCE_START),
+				Optional:            true,
+				Computed:            true,
+				Default:             int64default.StaticInt64(DEFAULT_SEQUENCE_START),
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.RequiresReplace(),
+					int64planmodifier.UseStateForUnknown(),
+				},
+				Validators: []validator.Int64{
+					int64validator.AtLeast(0),
+				},
+			},
+
+			"alphabet": schema.StringAttribute{
+				MarkdownDescription: fmt.Sprintf("Supply your own list of characters to use for the `{rand}` placeholder.\n"+
+					"Should be between 1 and 255 characters long.\n"+
+					"The default value is `%q`.", DEFAULT_DNS_ALPHABET),
+				Optional: true,
+				Computed: true,
+				Default:  stringdefault.StaticString(DEFAULT_DNS_ALPHABET),
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+					stringplanmodifier.UseStateForUnknown(),
+				},
+				Validators: []validator.String{
+					stringvalidator.LengthBetween(1, 255),
+				},
+			},
+
+			"keys": schema.SetAttribute{
+				MarkdownDescription: "The keys to hand out sequence numbers to. New keys are numbered in lexical order.",
+				ElementType:         types.StringType,
+				Required:            true,
+			},
+
+			"keepers": schema.MapAttribute{
+				Description: "Arbitrary map of values that, when changed, will hand out new numbers to every key. " +
+					"See [the main provider documentation](../index.html) for more information.",
+				ElementType: types.StringType,
+				Optional:    true,
+			},
+
+			"keepers_sensitive": schema.DynamicAttribute{
+				MarkdownDescription: "Arbitrary sensitive values, such as credential versions, that, when changed, will hand out new numbers to every key. " +
+					"The values are write-only: only their salted digest is stored, in `keepers_sensitive_digest`. Write-only attributes require Terraform 1.11 or later.",
+				Optional:  true,
+				Sensitive: true,
+				WriteOnly: true,
+			},
+
+			"keepers_sensitive_digest": schema.StringAttribute{
+				MarkdownDescription: "The salted SHA-256 digest of `keepers_sensitive`, of the form `sha256:<salt>:<digest>`, " +
+					"against which their configured values are compared.",
+				Computed: true,
+				PlanModifiers: []planmodifier.String{
+					keepersSensitiveDigestModifier{requiresReplace: false},
+				},
+			},
+
+			"numbers": schema.MapAttribute{
+				MarkdownDescription: "The sequence number of each key.",
+				ElementType:         types.Int64Type,
+				Computed:            true,
+			},
+
+			"values": schema.MapAttribute{
+				MarkdownDescription: "The rendered value of each key.",
+				ElementType:         types.StringType,
+				Computed:            true,
+			},
+
+			"high_water_mark": schema.Int64Attribute{
+				MarkdownDescription: "The highest sequence number handed out so far.",
+				Computed:            true,
+			},
+
+			"id": schema.StringAttribute{
+				MarkdownDescription: "The generated random string identifying the sequence.",
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+		},
+	}
+}
+
+func (d *SequenceResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerData, ok := req.ProviderData.(*NanoidProviderData)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *provider.NanoidProviderData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	d.providerData = providerData
+}
+
+func (r *SequenceResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data SequenceResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	id, err := gonanoid.Generate(DEFAULT_DNS_ALPHABET, DEFAULT_DNS_LENGTH)
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to generate id", fmt.Sprintf("Failed to generate id: %s.", err))
+		return
+	}
+
+	data.Id = types.StringValue(id)
+	data.HighWaterMark = types.Int64Value(data.Start.ValueInt64() - 1)
+	data.Numbers = types.MapValueMust(types.Int64Type, nil)
+	data.Values = types.MapValueMust(types.StringType, nil)
+	resp.Diagnostics.Append(r.assign(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(setKeepersSensitiveDigest(ctx, req.Config, &data.KeepersSensitiveDigest)...)
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (d *SequenceResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data SequenceResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	d.providerData.recordSequence(data.Pool.ValueString(), data.HighWaterMark.ValueInt64())
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *SequenceResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var data, state SequenceResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var digest types.String
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("keepers_sensitive_digest"), &digest)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	data.Id = state.Id
+	data.HighWaterMark = state.HighWaterMark
+	data.Numbers = state.Numbers
+	data.Values = state.Values
+	// Changed keepers hand out new numbers to every key, the retired ones are
+	// never reused.
+	if sequenceKeepersChanged(data.Keepers, state.Keepers) || digest.IsUnknown() {
+		data.Numbers = types.MapValueMust(types.Int64Type, nil)
+		data.Values = types.MapValueMust(types.StringType, nil)
+	}
+	resp.Diagnostics.Append(r.assign(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(setKeepersSensitiveDigest(ctx, req.Config, &data.KeepersSensitiveDigest)...)
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *SequenceResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	if req.State.Raw.IsNull() || req.Plan.Raw.IsNull() {
+		return
+	}
+
+	// Changed keepers hand out new numbers to every key, see Update.
+	var keepers, stateKeepers types.Map
+	var digest types.String
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("keepers"), &keepers)...)
+	resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root("keepers"), &stateKeepers)...)
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("keepers_sensitive_digest"), &digest)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !sequenceKeepersChanged(keepers, stateKeepers) && !digest.IsUnknown() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("numbers"), types.MapUnknown(types.Int64Type))...)
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("values"), types.MapUnknown(types.StringType))...)
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("high_water_mark"), types.Int64Unknown())...)
+}
+
+// sequenceKeepersChanged reports whether the configured keepers differ from
+// the state. Like for the other resources, removing the keepers keeps the
+// numbers.
+func sequenceKeepersChanged(keepers types.Map, stateKeepers types.Map) bool {
+	return !keepers.IsNull() && !keepers.Equal(stateKeepers)
+}
+
+func (r *SequenceResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var data SequenceResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// A sequence that replaces this one during the same apply continues after
+	// its numbers.
+	r.providerData.recordSequence(data.Pool.ValueString(), data.HighWaterMark.ValueInt64())
+}
+
+// assign keeps the numbers and values of the retained keys, drops the ones of
+// the removed keys and hands out the next numbers of the sequence to the new
+// keys.
+func (r *SequenceResource) assign(ctx context.Context, data *SequenceResourceModel) (diags diag.Diagnostics) {
+	tokens, err := parseSequenceFormat(data.Format.ValueString())
+	if err != nil {
+		diags.AddAttributeError(path.Root("format"), "Invalid format", fmt.Sprintf("Invalid format: %s.", err))
+		return diags
+	}
+
+	var keys []string
+	var previousNumbers map[string]int64
+	var previousValues map[string]string
+	diags.Append(data.Keys.ElementsAs(ctx, &keys, false)...)
+	diags.Append(data.Numbers.ElementsAs(ctx, &previousNumbers, false)...)
+	diags.Append(data.Values.ElementsAs(ctx, &previousValues, false)...)
+	if diags.HasError() {
+		return diags
+	}
+
+	sort.Strings(keys)
+	numbers := make(map[string]int64, len(keys))
+	values := make(map[string]string, len(keys))
+	var added []string
+	for _, key := range keys {
+		if number, ok := previousNumbers[key]; ok {
+			numbers[key] = number
+			values[key] = previousValues[key]
+			continue
+		}
+		added = append(added, key)
+	}
+
+	highWaterMark, err := r.providerData.reserveSequence(data.Pool.ValueString(), data.HighWaterMark.ValueInt64(), int64(len(added)))
+	if err != nil {
+		diags.AddError("Failed to generate id", fmt.Sprintf("Failed to generate id: %s.", err))
+		return diags
+	}
+
+	number := highWaterMark - int64(len(added))
+	for _, key := range added {
+		number++
+		value, err := renderSequenceFormat(tokens, data.Alphabet.ValueString(), key, number)
+		if err != nil {
+			diags.AddError("Failed to generate id", fmt.Sprintf("Failed to generate id: %s.", err))
+			return diags
+		}
+		numbers[key] = number
+		values[key] = value
+	}
+
+	var d diag.Diagnostics
+	data.Numbers, d = types.MapValueFrom(ctx, types.Int64Type, numbers)
+	diags.Append(d...)
+	data.Values, d = types.MapValueFrom(ctx, types.StringType, values)
+	diags.Append(d...)
+	data.HighWaterMark = types.Int64Value(highWaterMark)
+	return diags
+}
+
+// reserveSequence hands out count numbers of the pool after both the given
+// high-water mark and the numbers already handed out in the pool, and returns
+// the last of them.
+func (p *NanoidProviderData) reserveSequence(pool string, highWaterMark int64, count int64) (int64, error) {
+	if p == nil {
+		return 0, fmt.Errorf("the provider has not been configured")
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.sequences[pool] > highWaterMark {
+		highWaterMark = p.sequences[pool]
+	}
+	highWaterMark += count
+	p.sequences[pool] = highWaterMark
+	return highWaterMark, nil
+}
+
+// recordSequence records that the numbers of the pool up to highWaterMark
+// have been handed out.
+func (p *NanoidProviderData) recordSequence(pool string, highWaterMark int64) {
+	if p == nil {
+		return
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.sequences[pool] < highWaterMark {
+		p.sequences[pool] = highWaterMark
+	}
+}
+
+var sequencePlaceholder = regexp.MustCompile(`\{([a-z]+)(?::([0-9]+))?\}`)
+
+// sequenceToken is either a literal, when name is empty, or a placeholder.
+type sequenceToken struct {
+	literal string
+	name    string
+	width   int
+}
+
+func parseSequenceFormat(format string) ([]sequenceToken, error) {
+	var tokens []sequenceToken
+	last := 0
+	hasSeq := false
+	for _, match := range sequencePlaceholder.FindAllStringSubmatchIndex(format, -1) {
+		if match[0] > last {
+			tokens = append(tokens, sequenceToken{literal: format[last:match[0]]})
+		}
+		last = match[1]
+
+		token := sequenceToken{name: format[match[2]:match[3]]}
+		if match[4] >= 0 {
+			width, err := strconv.Atoi(format[match[4]:match[5]])
+			if err != nil || width < 1 || width > 64 {
+				return nil, fmt.Errorf("the width of %q must be between 1 and 64", format[match[0]:match[1]])
+			}
+			token.width = width
+		}
+
+		switch token.name {
+		case "seq":
+			hasSeq = true
+		case "rand":
+			if token.width == 0 {
+				token.width = DEFAULT_SEQUENCE_RAND_LENGTH
+			}
+		case "key":
+			if token.width != 0 {
+				return nil, fmt.Errorf("the %q placeholder does not accept a width", format[match[0]:match[1]])
+			}
+		default:
+			return nil, fmt.Errorf("unknown placeholder %q", format[match[0]:match[1]])
+		}
+		tokens = append(tokens, token)
+	}
+	if last < len(format) {
+		tokens = append(tokens, sequenceToken{literal: format[last:]})
+	}
+
+	if !hasSeq {
+		return nil, fmt.Errorf("the format must contain the {seq} placeholder")
+	}
+
+	return tokens, nil
+}
+
+func renderSequenceFormat(tokens []sequenceToken, alphabet string, key string, number int64) (string, error) {
+	var b strings.Builder
+	for _, token := range tokens {
+		switch token.name {
+		case "":
+			b.WriteString(token.literal)
+		case "seq":
+			b.WriteString(fmt.Sprintf("%0*d", token.width, number))
+		case "rand":
+			rand, err := gonanoid.Generate(alphabet, token.width)
+			if err != nil {
+				return "", err
+			}
+			b.WriteString(rand)
+		case "key":
+			b.WriteString(key)
+		}
+	}
+
+	return b.String(), nil
+}
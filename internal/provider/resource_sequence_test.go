@@ -0,0 +1,223 @@
+// Copyright (c) The Nanoid Provider for Terraform Authors
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/plancheck"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+)
+
+func TestAccSequenceResource(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccSequenceResourceConfig("env-{seq:04}-{rand:4}", "a", "b"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("nanoid_sequence.test", "start", "1"),
+					resource.TestCheckResourceAttr("nanoid_sequence.test", "high_water_mark", "2"),
+					resource.TestCheckResourceAttr("nanoid_sequence.test", "numbers.a", "1"),
+					resource.TestCheckResourceAttr("nanoid_sequence.test", "numbers.b", "2"),
+					resource.TestMatchResourceAttr("nanoid_sequence.test", "values.a", regexp.MustCompile(`^env-0001-[0-9a-z]{4}$`)),
+					resource.TestMatchResourceAttr("nanoid_sequence.test", "values.b", regexp.MustCompile(`^env-0002-[0-9a-z]{4}$`)),
+				),
+			},
+			{
+				Config: testAccSequenceResourceConfig("env-{seq:04}-{rand:4}", "b", "c"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("nanoid_sequence.test", "high_water_mark", "3"),
+					resource.TestCheckNoResourceAttr("nanoid_sequence.test", "numbers.a"),
+					resource.TestCheckResourceAttr("nanoid_sequence.test", "numbers.b", "2"),
+					resource.TestCheckResourceAttr("nanoid_sequence.test", "numbers.c", "3"),
+					resource.TestMatchResourceAttr("nanoid_sequence.test", "values.c", regexp.MustCompile(`^env-0003-[0-9a-z]{4}$`)),
+				),
+			},
+			{
+				Config: testAccSequenceResourceConfig("env-{seq:04}-{rand:4}", "a", "b", "c"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("nanoid_sequence.test", "high_water_mark", "4"),
+					resource.TestCheckResourceAttr("nanoid_sequence.test", "numbers.a", "4"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccSequenceResource_KeepsValues(t *testing.T) {
+	var value string
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccSequenceResourceConfig("{key}-{seq}-{rand}", "a"),
+				Check: resource.TestCheckResourceAttrWith("nanoid_sequence.test", "values.a", func(input string) error {
+					value = input
+					return nil
+				}),
+			},
+			{
+				Config: testAccSequenceResourceConfig("{key}-{seq}-{rand}", "a", "b"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttrWith("nanoid_sequence.test", "values.a", func(input string) error {
+						if input != value {
+							return fmt.Errorf("expected %q to be kept, got %q", value, input)
+						}
+						return nil
+					}),
+					resource.TestMatchResourceAttr("nanoid_sequence.test", "values.b", regexp.MustCompile(`^b-2-[0-9a-z]{4}$`)),
+				),
+			},
+		},
+	})
+}
+
+func TestAccSequenceResource_InvalidFormat(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      testAccSequenceResourceConfig("env-{rand:4}", "a"),
+				ExpectError: regexp.MustCompile(`must contain the {seq} placeholder`),
+			},
+			{
+				Config:      testAccSequenceResourceConfig("env-{seq}-{name}", "a"),
+				ExpectError: regexp.MustCompile(`unknown placeholder`),
+			},
+		},
+	})
+}
+
+func TestAccSequenceResource_Keepers(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccSequenceResourceConfigKeepers("first", "a", "b"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("nanoid_sequence.test", "numbers.a", "1"),
+					resource.TestCheckResourceAttr("nanoid_sequence.test", "numbers.b", "2"),
+				),
+			},
+			{
+				Config: testAccSequenceResourceConfigKeepers("second", "a", "b"),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("nanoid_sequence.test", plancheck.ResourceActionUpdate),
+					},
+				},
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("nanoid_sequence.test", "high_water_mark", "4"),
+					resource.TestCheckResourceAttr("nanoid_sequence.test", "numbers.a", "3"),
+					resource.TestCheckResourceAttr("nanoid_sequence.test", "numbers.b", "4"),
+				),
+			},
+			{
+				Config: `
+resource "nanoid_sequence" "test" {
+  keys = ["a", "b"]
+}
+`,
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("nanoid_sequence.test", plancheck.ResourceActionUpdate),
+					},
+				},
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckNoResourceAttr("nanoid_sequence.test", "keepers.%"),
+					resource.TestCheckResourceAttr("nanoid_sequence.test", "high_water_mark", "4"),
+					resource.TestCheckResourceAttr("nanoid_sequence.test", "numbers.a", "3"),
+					resource.TestCheckResourceAttr("nanoid_sequence.test", "numbers.b", "4"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccSequenceResource_Pool(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+resource "nanoid_sequence" "test" {
+  count = 4
+  keys  = ["a", "b"]
+}
+`,
+				Check: func(s *terraform.State) error {
+					seen := make(map[string]string)
+					for i := 0; i < 4; i++ {
+						name := fmt.Sprintf("nanoid_sequence.test.%d", i)
+						attributes := s.RootModule().Resources[name].Primary.Attributes
+						for _, key := range []string{"a", "b"} {
+							number := attributes["numbers."+key]
+							if other, ok := seen[number]; ok {
+								return fmt.Errorf("%s and %s.%s share the number %s", other, name, key, number)
+							}
+							seen[number] = name + "." + key
+						}
+					}
+					return nil
+				},
+			},
+		},
+	})
+}
+
+func TestAccSequenceResource_Replace(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccSequenceResourceConfig("{seq}", "a", "b"),
+				Check:  resource.TestCheckResourceAttr("nanoid_sequence.test", "high_water_mark", "2"),
+			},
+			{
+				Config: testAccSequenceResourceConfig("{seq:04}", "a", "b"),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("nanoid_sequence.test", plancheck.ResourceActionDestroyBeforeCreate),
+					},
+				},
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("nanoid_sequence.test", "values.a", "0003"),
+					resource.TestCheckResourceAttr("nanoid_sequence.test", "values.b", "0004"),
+				),
+			},
+		},
+	})
+}
+
+func testAccSequenceResourceConfigKeepers(keeper string, keys ...string) string {
+	return fmt.Sprintf(`
+resource "nanoid_sequence" "test" {
+  keys = ["%s"]
+
+  keepers = {
+    keeper = %q
+  }
+}
+`, strings.Join(keys, `", "`), keeper)
+}
+
+func testAccSequenceResourceConfig(format string, keys ...string) string {
+	return fmt.Sprintf(`
+resource "nanoid_sequence" "test" {
+  format = %q
+  keys   = ["%s"]
+}
+`, format, strings.Join(keys, `", "`))
+}
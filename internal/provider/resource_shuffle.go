@@ -0,0 +1,287 @@
+// Copyright (c) The Nanoid Provider for Terraform Authors
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"sort"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/mapplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	gonanoid "github.com/matoous/go-nanoid"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ resource.Resource = &ShuffleResource{}
+var _ resource.ResourceWithImportState = &ShuffleResource{}
+
+func NewShuffleResource() resource.Resource {
+	return &ShuffleResource{}
+}
+
+// ShuffleResource defines the resource implementation.
+type ShuffleResource struct{}
+
+// ShuffleResourceModel describes the resource data model.
+type ShuffleResourceModel struct {
+	Id                     types.String  `tfsdk:"id"`
+	Input                  types.List    `tfsdk:"input"`
+	ResultCount            types.Int64   `tfsdk:"result_count"`
+	Seed                   types.String  `tfsdk:"seed"`
+	Keepers                types.Map     `tfsdk:"keepers"`
+	KeepersSensitive       types.Dynamic `tfsdk:"keepers_sensitive"`
+	KeepersSensitiveDigest types.String  `tfsdk:"keepers_sensitive_digest"`
+	Result                 types.List    `tfsdk:"result"`
+}
+
+func (d *ShuffleResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_shuffle"
+}
+
+func (d *ShuffleResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "The shuffle resource generates a stable random permutation of a list, driven by a nanoid seed kept in state.\n\n" +
+			"The permutation sorts the elements of `input` by `sha256(\"<seed>:<element>\")`, compared as big-endian unsigned integers, " +
+			"keeping the input order of equal elements. " +
+			"The result is the first `result_count` elements of the permutation. " +
+			"This allows the selection to be reproduced outside of Terraform from the `seed`.\n\n" +
+			"Changes to `input` and `result_count` are applied in place and keep the order stable: " +
+			"the remaining elements keep their relative order, removed elements are dropped and added elements are inserted at their rank. " +
+			"Only a change of the `seed` or the `keepers` reshuffles the whole list.",
+		Attributes: map[string]schema.Attribute{
+			"input": schema.ListAttribute{
+				MarkdownDescription: "The list of strings to shuffle.",
+				ElementType:         types.StringType,
+				Required:            true,
+			},
+
+			"result_count": schema.Int64Attribute{
+				MarkdownDescription: "The number of elements to select from the permutation.\n" +
+					"Should be at most the number of elements of `input`.\n" +
+					"Defaults to the number of elements of `input`.",
+				Optional: true,
+				Validators: []validator.Int64{
+					int64validator.AtLeast(0),
+				},
+			},
+
+			"seed": schema.StringAttribute{
+				MarkdownDescription: "The seed driving the permutation.\n" +
+					"Defaults to a nanoid generated with the default alphabet and length of the `nanoid_id` resource.",
+				Optional: true,
+				Computed: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+					stringplanmodifier.UseStateForUnknown(),
+				},
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+				},
+			},
+
+			"keepers": schema.MapAttribute{
+				Description: "Arbitrary map of values that, when changed, will trigger recreation of " +
+					"resource. See [the main provider documentation](../index.html) for more information.",
+				ElementType: types.StringType,
+				Optional:    true,
+				PlanModifiers: []planmodifier.Map{
+					mapplanmodifier.RequiresReplaceIfConfigured(),
+				},
+			},
+
+			"keepers_sensitive": schema.DynamicAttribute{
+				MarkdownDescription: "Arbitrary sensitive values, such as credential versions, that, when changed, will trigger recreation of resource. " +
+					"The values are write-only: only their salted digest is stored, in `keepers_sensitive_digest`. Write-only attributes require Terraform 1.11 or later.",
+				Optional:  true,
+				Sensitive: true,
+				WriteOnly: true,
+			},
+
+			"keepers_sensitive_digest": schema.StringAttribute{
+				MarkdownDescription: "The salted SHA-256 digest of `keepers_sensitive`, of the form `sha256:<salt>:<digest>`, " +
+					"against which their configured values are compared.",
+				Computed: true,
+				PlanModifiers: []planmodifier.String{
+					keepersSensitiveDigestModifier{requiresReplace: true},
+				},
+			},
+
+			"result": schema.ListAttribute{
+				MarkdownDescription: "The selected elements, in the order of the permutation.",
+				ElementType:         types.StringType,
+				Computed:            true,
+			},
+
+			"id": schema.StringAttribute{
+				MarkdownDescription: "The seed driving the permutation.",
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+		},
+	}
+}
+
+func (d *ShuffleResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	_, ok := req.ProviderData.(*NanoidProviderData)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *provider.NanoidProviderData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+}
+
+func (r *ShuffleResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data ShuffleResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if data.Seed.IsUnknown() || data.Seed.IsNull() {
+		seed, err := gonanoid.Generate(DEFAULT_ID_ALPHABET, DEFAULT_ID_LENGTH)
+		if err != nil {
+			resp.Diagnostics.AddError("Failed to generate id", fmt.Sprintf("Failed to generate id: %s.", err))
+			return
+		}
+		data.Seed = types.StringValue(seed)
+	}
+
+	resp.Diagnostics.Append(data.shuffle(ctx)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(setKeepersSensitiveDigest(ctx, req.Config, &data.KeepersSensitiveDigest)...)
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (d *ShuffleResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data ShuffleResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *ShuffleResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var data ShuffleResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// The rank of an element only depends on the seed, so changes to the
+	// input or the result count are applied in place without reshuffling.
+	resp.Diagnostics.Append(data.shuffle(ctx)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(setKeepersSensitiveDigest(ctx, req.Config, &data.KeepersSensitiveDigest)...)
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *ShuffleResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var data ShuffleResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+func (r *ShuffleResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	if req.ID == "" {
+		resp.Diagnostics.AddError("Invalid id", "The id must be the seed of the permutation.")
+		return
+	}
+
+	// The input is only known once the configuration is applied, which
+	// computes the result from the imported seed in place.
+	state := &ShuffleResourceModel{
+		Id:                     types.StringValue(req.ID),
+		Seed:                   types.StringValue(req.ID),
+		Input:                  types.ListNull(types.StringType),
+		ResultCount:            types.Int64Null(),
+		Keepers:                types.MapNull(types.StringType),
+		KeepersSensitive:       types.DynamicNull(),
+		KeepersSensitiveDigest: types.StringNull(),
+		Result:                 types.ListNull(types.StringType),
+	}
+
+	diags := resp.State.Set(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+// shuffle computes the result from the seed, the input and the result count.
+func (data *ShuffleResourceModel) shuffle(ctx context.Context) (diags diag.Diagnostics) {
+	var input []string
+	diags.Append(data.Input.ElementsAs(ctx, &input, false)...)
+	if diags.HasError() {
+		return diags
+	}
+
+	count := int64(len(input))
+	if !data.ResultCount.IsNull() {
+		count = data.ResultCount.ValueInt64()
+	}
+	if count > int64(len(input)) {
+		diags.AddAttributeError(path.Root("result_count"), "Invalid result count",
+			fmt.Sprintf("The result count %d is greater than the number of elements of the input, %d.", count, len(input)))
+		return diags
+	}
+
+	result := shufflePermutation(data.Seed.ValueString(), input)[:count]
+
+	var d diag.Diagnostics
+	data.Result, d = types.ListValueFrom(ctx, types.StringType, result)
+	diags.Append(d...)
+	data.Id = data.Seed
+	return diags
+}
+
+// shufflePermutation returns a copy of the input sorted by
+// sha256("<seed>:<element>"). Ranking every element on its own, rather than
+// by its index, keeps the relative order of the elements when others are
+// added to or removed from the input.
+func shufflePermutation(seed string, input []string) []string {
+	ranks := make(map[string][]byte, len(input))
+	for _, v := range input {
+		sum := sha256.Sum256([]byte(seed + ":" + v))
+		ranks[v] = sum[:]
+	}
+
+	result := append([]string{}, input...)
+	sort.SliceStable(result, func(i, j int) bool {
+		return bytes.Compare(ranks[result[i]], ranks[result[j]]) < 0
+	})
+
+	return result
+}
@@ -0,0 +1,156 @@
+// Copyright (c) The Nanoid Provider for Terraform Authors
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/plancheck"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+)
+
+func TestAccShuffleResource(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccShuffleResourceConfig(`"a", "b", "c", "d", "e"`, "", 2),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("nanoid_shuffle.test", "result.#", "2"),
+					resource.TestCheckResourceAttrWith("nanoid_shuffle.test", "seed", testCheckLen(21)),
+					resource.TestCheckResourceAttrPair("nanoid_shuffle.test", "id", "nanoid_shuffle.test", "seed"),
+				),
+			},
+			{
+				ResourceName:            "nanoid_shuffle.test",
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"input", "result", "result_count"},
+			},
+		},
+	})
+}
+
+func TestAccShuffleResource_WithSeed(t *testing.T) {
+	expected := shufflePermutation("seed", []string{"a", "b", "c", "d", "e"})
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccShuffleResourceConfig(`"a", "b", "c", "d", "e"`, "seed", 0),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("nanoid_shuffle.test", "seed", "seed"),
+					resource.TestCheckResourceAttr("nanoid_shuffle.test", "result.#", "5"),
+					resource.TestCheckResourceAttr("nanoid_shuffle.test", "result.0", expected[0]),
+					resource.TestCheckResourceAttr("nanoid_shuffle.test", "result.4", expected[4]),
+				),
+			},
+			{
+				Config: testAccShuffleResourceConfig(`"a", "b", "c", "d", "e"`, "seed", 3),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("nanoid_shuffle.test", "seed", "seed"),
+					resource.TestCheckResourceAttr("nanoid_shuffle.test", "result.#", "3"),
+					resource.TestCheckResourceAttr("nanoid_shuffle.test", "result.0", expected[0]),
+					resource.TestCheckResourceAttr("nanoid_shuffle.test", "result.2", expected[2]),
+				),
+			},
+		},
+	})
+}
+
+func TestShufflePermutation(t *testing.T) {
+	input := []string{"a", "b", "c", "d", "e", "f", "g", "h"}
+	first := shufflePermutation("seed", input)
+	if strings.Join(first, "") != strings.Join(shufflePermutation("seed", input), "") {
+		t.Fatalf("expected the permutation to be stable")
+	}
+	if strings.Join(input, "") != "abcdefgh" {
+		t.Fatalf("expected the input to be left untouched")
+	}
+
+	// sorted("abcdefgh", key=lambda v: hashlib.sha256(b"seed:" + v.encode()).digest())
+	if strings.Join(first, "") != "fagedcbh" {
+		t.Fatalf("expected the permutation fagedcbh, got %s", strings.Join(first, ""))
+	}
+
+	// Replacing a with x keeps the order of the other elements.
+	changed := shufflePermutation("seed", []string{"x", "b", "c", "d", "e", "f", "g", "h"})
+	var remaining []string
+	for _, v := range changed {
+		if v != "x" {
+			remaining = append(remaining, v)
+		}
+	}
+	if len(remaining) != 7 || strings.Join(remaining, "") != "fgedcbh" {
+		t.Fatalf("expected the remaining elements to keep the order fgedcbh, got %s", strings.Join(remaining, ""))
+	}
+}
+
+func TestAccShuffleResource_InputChange(t *testing.T) {
+	before := shufflePermutation("seed", []string{"a", "b", "c", "d", "e"})
+	after := shufflePermutation("seed", []string{"b", "c", "d", "e", "f"})
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccShuffleResourceConfig(`"a", "b", "c", "d", "e"`, "seed", 0),
+				Check:  resource.TestCheckResourceAttr("nanoid_shuffle.test", "result.0", before[0]),
+			},
+			{
+				Config: testAccShuffleResourceConfig(`"b", "c", "d", "e", "f"`, "seed", 0),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("nanoid_shuffle.test", plancheck.ResourceActionUpdate),
+					},
+				},
+				Check: func(s *terraform.State) error {
+					attributes := s.RootModule().Resources["nanoid_shuffle.test"].Primary.Attributes
+					var result, remaining []string
+					for i := range after {
+						result = append(result, attributes[fmt.Sprintf("result.%d", i)])
+					}
+					for _, v := range before {
+						if v != "a" {
+							remaining = append(remaining, v)
+						}
+					}
+					var kept []string
+					for _, v := range result {
+						if v != "f" {
+							kept = append(kept, v)
+						}
+					}
+					if strings.Join(kept, ",") != strings.Join(remaining, ",") {
+						return fmt.Errorf("expected the remaining elements to keep the order %v, got %v", remaining, result)
+					}
+					return nil
+				},
+			},
+		},
+	})
+}
+
+func testAccShuffleResourceConfig(input string, seed string, resultCount int) string {
+	seedStr := ""
+	if seed != "" {
+		seedStr = fmt.Sprintf("seed = %q", seed)
+	}
+	resultCountStr := ""
+	if resultCount != 0 {
+		resultCountStr = fmt.Sprintf("result_count = %d", resultCount)
+	}
+	return fmt.Sprintf(`
+resource "nanoid_shuffle" "test" {
+  input = [%s]
+  %s
+  %s
+}
+`, input, seedStr, resultCountStr)
+}
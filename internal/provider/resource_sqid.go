@@ -0,0 +1,319 @@
+// Copyright (c) The Nanoid Provider for Terraform Authors
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"crypto/rand"
+	"fmt"
+	"math/big"
+	"slices"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/listvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64default"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/mapplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ resource.Resource = &SqidResource{}
+var _ resource.ResourceWithImportState = &SqidResource{}
+
+func NewSqidResource() resource.Resource {
+	return &SqidResource{}
+}
+
+// SqidResource defines the resource implementation.
+type SqidResource struct{}
+
+// SqidResourceModel describes the resource data model.
+type SqidResourceModel struct {
+	Id                     types.String  `tfsdk:"id"`
+	Alphabet               types.String  `tfsdk:"alphabet"`
+	MinLength              types.Int64   `tfsdk:"min_length"`
+	Numbers                types.List    `tfsdk:"numbers"`
+	Keepers                types.Map     `tfsdk:"keepers"`
+	KeepersSensitive       types.Dynamic `tfsdk:"keepers_sensitive"`
+	KeepersSensitiveDigest types.String  `tfsdk:"keepers_sensitive_digest"`
+	ShuffledAlphabet       types.String  `tfsdk:"shuffled_alphabet"`
+	Sqid                   types.String  `tfsdk:"sqid"`
+}
+
+func (d *SqidResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_sqid"
+}
+
+func (d *SqidResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "The sqid resource encodes a list of non-negative integers into a short id with [Sqids](https://sqids.org), " +
+			"which can be decoded back to the integers.\n\n" +
+			"The alphabet is randomly shuffled once and kept in state as `shuffled_alphabet`, so that ids cannot be decoded without it. " +
+			"Other services decode identically by using the `shuffled_alphabet` with any Sqids library, or with the `sqids_encode` and " +
+			"`sqids_decode` functions. Ids containing a word of the default Sqids blocklist of profanities are never generated, as with other Sqids libraries.\n\n" +
+			"Existing alphabets can be imported with the `shuffled_alphabet` as the id.",
+		Attributes: map[string]schema.Attribute{
+			"alphabet": schema.StringAttribute{
+				MarkdownDescription: "The ASCII characters to shuffle, without duplicates.\n" +
+					"Should contain at least 3 characters.\n" +
+					"Changing the characters of the alphabet triggers recreation of the resource.\n" +
+					"The default value is the Sqids alphabet, `" + DEFAULT_SQIDS_ALPHABET + "`.",
+				Optional: true,
+				Computed: true,
+				Default:  stringdefault.StaticString(DEFAULT_SQIDS_ALPHABET),
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplaceIf(
+						sqidAlphabetCharactersChanged,
+						"If the characters of the alphabet change, Terraform will destroy and recreate the resource.",
+						"If the characters of the alphabet change, Terraform will destroy and recreate the resource.",
+					),
+				},
+				Validators: []validator.String{
+					isSqidsAlphabet(),
+				},
+			},
+
+			"min_length": schema.Int64Attribute{
+				MarkdownDescription: fmt.Sprintf("The minimum length of the sqid, padded with characters that are ignored when decoding.\n"+
+					"Should be between 0 and %d.\n"+
+					"The default value is %d.", MAX_SQIDS_MIN_LENGTH, DEFAULT_SQIDS_MIN_LENGTH),
+				Optional: true,
+				Computed: true,
+				Default:  int64default.StaticInt64(DEFAULT_SQIDS_MIN_LENGTH),
+				Validators: []validator.Int64{
+					int64validator.Between(0, MAX_SQIDS_MIN_LENGTH),
+				},
+			},
+
+			"numbers": schema.ListAttribute{
+				MarkdownDescription: "The non-negative integers to encode. Changes are applied in place with the same shuffled alphabet.",
+				ElementType:         types.Int64Type,
+				Required:            true,
+				Validators: []validator.List{
+					listvalidator.SizeAtLeast(1),
+					listvalidator.ValueInt64sAre(int64validator.AtLeast(0)),
+				},
+			},
+
+			"keepers": schema.MapAttribute{
+				Description: "Arbitrary map of values that, when changed, will trigger recreation of " +
+					"resource. See [the main provider documentation](../index.html) for more information.",
+				ElementType: types.StringType,
+				Optional:    true,
+				PlanModifiers: []planmodifier.Map{
+					mapplanmodifier.RequiresReplaceIfConfigured(),
+				},
+			},
+
+			"keepers_sensitive": schema.DynamicAttribute{
+				MarkdownDescription: "Arbitrary sensitive values, such as credential versions, that, when changed, will trigger recreation of resource. " +
+					"The values are write-only: only their salted digest is stored, in `keepers_sensitive_digest`. Write-only attributes require Terraform 1.11 or later.",
+				Optional:  true,
+				Sensitive: true,
+				WriteOnly: true,
+			},
+
+			"keepers_sensitive_digest": schema.StringAttribute{
+				MarkdownDescription: "The salted SHA-256 digest of `keepers_sensitive`, of the form `sha256:<salt>:<digest>`, " +
+					"against which their configured values are compared.",
+				Computed: true,
+				PlanModifiers: []planmodifier.String{
+					keepersSensitiveDigestModifier{requiresReplace: true},
+				},
+			},
+
+			"shuffled_alphabet": schema.StringAttribute{
+				MarkdownDescription: "The randomly shuffled alphabet, to use for encoding and decoding.",
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+
+			"sqid": schema.StringAttribute{
+				MarkdownDescription: "The encoded numbers.",
+				Computed:            true,
+			},
+
+			"id": schema.StringAttribute{
+				MarkdownDescription: "The shuffled alphabet.",
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+		},
+	}
+}
+
+func (d *SqidResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	_, ok := req.ProviderData.(*NanoidProviderData)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *provider.NanoidProviderData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+}
+
+func (r *SqidResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data SqidResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if _, err := newSqids(data.Alphabet.ValueString(), DEFAULT_SQIDS_MIN_LENGTH); err != nil {
+		resp.Diagnostics.AddAttributeError(path.Root("alphabet"), "Invalid alphabet", fmt.Sprintf("Invalid alphabet: %s.", err))
+		return
+	}
+
+	alphabet := []byte(data.Alphabet.ValueString())
+	for i := len(alphabet) - 1; i > 0; i-- {
+		j, err := rand.Int(rand.Reader, big.NewInt(int64(i+1)))
+		if err != nil {
+			resp.Diagnostics.AddError("Failed to generate id", fmt.Sprintf("Failed to generate id: %s.", err))
+			return
+		}
+		alphabet[i], alphabet[j.Int64()] = alphabet[j.Int64()], alphabet[i]
+	}
+	data.ShuffledAlphabet = types.StringValue(string(alphabet))
+	data.Id = data.ShuffledAlphabet
+
+	resp.Diagnostics.Append(data.encode(ctx)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(setKeepersSensitiveDigest(ctx, req.Config, &data.KeepersSensitiveDigest)...)
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (d *SqidResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data SqidResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *SqidResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var data SqidResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// The shuffled alphabet is kept, so changes to the numbers or the minimum
+	// length are applied in place.
+	resp.Diagnostics.Append(data.encode(ctx)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(setKeepersSensitiveDigest(ctx, req.Config, &data.KeepersSensitiveDigest)...)
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *SqidResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var data SqidResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+func (r *SqidResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	if _, err := newSqids(req.ID, DEFAULT_SQIDS_MIN_LENGTH); err != nil {
+		resp.Diagnostics.AddError("Invalid id", fmt.Sprintf("The id must be a shuffled alphabet: %s.", err))
+		return
+	}
+
+	// The numbers are only known once the configuration is applied, which
+	// computes the sqid from the imported alphabet in place.
+	state := &SqidResourceModel{
+		Id:                     types.StringValue(req.ID),
+		Alphabet:               types.StringValue(req.ID),
+		MinLength:              types.Int64Value(DEFAULT_SQIDS_MIN_LENGTH),
+		Numbers:                types.ListNull(types.Int64Type),
+		Keepers:                types.MapNull(types.StringType),
+		KeepersSensitive:       types.DynamicNull(),
+		KeepersSensitiveDigest: types.StringNull(),
+		ShuffledAlphabet:       types.StringValue(req.ID),
+		Sqid:                   types.StringNull(),
+	}
+
+	diags := resp.State.Set(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+// encode computes the sqid of the numbers with the shuffled alphabet.
+func (data *SqidResourceModel) encode(ctx context.Context) (diags diag.Diagnostics) {
+	var numbers []int64
+	diags.Append(data.Numbers.ElementsAs(ctx, &numbers, false)...)
+	if diags.HasError() {
+		return diags
+	}
+
+	s, err := newSqids(data.ShuffledAlphabet.ValueString(), int(data.MinLength.ValueInt64()))
+	if err != nil {
+		diags.AddAttributeError(path.Root("alphabet"), "Invalid alphabet", fmt.Sprintf("Invalid alphabet: %s.", err))
+		return diags
+	}
+
+	sqid, err := s.encode(sqidsNumbers(numbers))
+	if err != nil {
+		diags.AddError("Failed to generate id", fmt.Sprintf("Failed to generate id: %s.", err))
+		return diags
+	}
+
+	data.Sqid = types.StringValue(sqid)
+	return diags
+}
+
+// sqidsNumbers converts non-negative integers for encoding.
+func sqidsNumbers(numbers []int64) []uint64 {
+	result := make([]uint64, len(numbers))
+	for i, n := range numbers {
+		result[i] = uint64(n)
+	}
+
+	return result
+}
+
+// sqidAlphabetCharactersChanged requires a replacement when the configured
+// alphabet is not a permutation of the shuffled alphabet.
+func sqidAlphabetCharactersChanged(ctx context.Context, req planmodifier.StringRequest, resp *stringplanmodifier.RequiresReplaceIfFuncResponse) {
+	var shuffled types.String
+	resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root("shuffled_alphabet"), &shuffled)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	planned := []byte(req.PlanValue.ValueString())
+	current := []byte(shuffled.ValueString())
+	slices.Sort(planned)
+	slices.Sort(current)
+	resp.RequiresReplace = !slices.Equal(planned, current)
+}
@@ -0,0 +1,158 @@
+// Copyright (c) The Nanoid Provider for Terraform Authors
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+)
+
+func TestAccSqidResource(t *testing.T) {
+	var alphabet string
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccSqidResourceConfig("[1, 2, 3]"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("nanoid_sqid.test", "alphabet", DEFAULT_SQIDS_ALPHABET),
+					resource.TestCheckResourceAttr("nanoid_sqid.test", "min_length", "0"),
+					resource.TestCheckResourceAttrPair("nanoid_sqid.test", "id", "nanoid_sqid.test", "shuffled_alphabet"),
+					resource.TestCheckResourceAttrWith("nanoid_sqid.test", "shuffled_alphabet", func(input string) error {
+						sorted, expected := []byte(input), []byte(DEFAULT_SQIDS_ALPHABET)
+						slices.Sort(sorted)
+						slices.Sort(expected)
+						if !slices.Equal(sorted, expected) {
+							return fmt.Errorf("expected %q to be a permutation of the alphabet", input)
+						}
+						alphabet = input
+						return nil
+					}),
+					testAccCheckSqid("nanoid_sqid.test", 1, 2, 3),
+				),
+			},
+			{
+				Config: testAccSqidResourceConfig("[42]"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttrWith("nanoid_sqid.test", "shuffled_alphabet", func(input string) error {
+						if input != alphabet {
+							return fmt.Errorf("expected the shuffled alphabet %q to be kept, got %q", alphabet, input)
+						}
+						return nil
+					}),
+					testAccCheckSqid("nanoid_sqid.test", 42),
+				),
+			},
+			{
+				ResourceName:            "nanoid_sqid.test",
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"alphabet", "numbers", "sqid"},
+			},
+		},
+	})
+}
+
+func TestAccSqidResource_WithAlphabetAndMinLength(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+resource "nanoid_sqid" "test" {
+  alphabet   = "0123456789abcdef"
+  min_length = 12
+  numbers    = [7, 0, 1234567]
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestMatchResourceAttr("nanoid_sqid.test", "sqid", regexp.MustCompile(`^[0-9a-f]{12,}$`)),
+					testAccCheckSqid("nanoid_sqid.test", 7, 0, 1234567),
+				),
+			},
+		},
+	})
+}
+
+func TestAccSqidResource_InvalidAlphabet(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+resource "nanoid_sqid" "test" {
+  alphabet = "abca"
+  numbers  = [1]
+}
+`,
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile(`the\s+alphabet\s+contains\s+the\s+character\s+'a'\s+more\s+than\s+once`),
+			},
+			{
+				Config: `
+resource "nanoid_sqid" "test" {
+  alphabet = "abcé"
+  numbers  = [1]
+}
+`,
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile(`the\s+character\s+'é'\s+at\s+position\s+3\s+is\s+not\s+an\s+ASCII\s+character`),
+			},
+			{
+				Config: `
+resource "nanoid_sqid" "test" {
+  alphabet = "ab"
+  numbers  = [1]
+}
+`,
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile(`the\s+alphabet\s+must\s+contain\s+at\s+least\s+3\s+characters`),
+			},
+		},
+	})
+}
+
+// testAccCheckSqid decodes the sqid of the resource with its shuffled alphabet.
+func testAccCheckSqid(name string, numbers ...uint64) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		attributes := s.RootModule().Resources[name].Primary.Attributes
+		minLength, err := strconv.Atoi(attributes["min_length"])
+		if err != nil {
+			return err
+		}
+		sq, err := newSqids(attributes["shuffled_alphabet"], minLength)
+		if err != nil {
+			return err
+		}
+		decoded, err := sq.decode(attributes["sqid"])
+		if err != nil {
+			return err
+		}
+		if !slices.Equal(decoded, numbers) {
+			return fmt.Errorf("expected %q to decode to %v, got %v", attributes["sqid"], numbers, decoded)
+		}
+		if len(attributes["sqid"]) < minLength {
+			return fmt.Errorf("expected %q to have at least %d characters", attributes["sqid"], minLength)
+		}
+
+		return nil
+	}
+}
+
+func testAccSqidResourceConfig(numbers string) string {
+	return fmt.Sprintf(`
+resource "nanoid_sqid" "test" {
+  numbers = %s
+}
+`, numbers)
+}
@@ -0,0 +1,138 @@
+// Copyright (c) The Nanoid Provider for Terraform Authors
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// SEEDED_ID_ALGORITHM names the algorithm deriving the ids of nanoid_id and
+// nanoid_dns resources from a seed. Seeded ids are only reproducible by the
+// same algorithm, so any change to the derivation must change its name.
+const SEEDED_ID_ALGORITHM = "nanoid-v2-hmac-drbg-sha256"
+
+// hmacDrbg is the HMAC_DRBG of NIST SP 800-90A, instantiated with SHA-256
+// and never reseeded, which derives a deterministic stream of bytes from its
+// seed material.
+type hmacDrbg struct {
+	k []byte
+	v []byte
+}
+
+func newHmacDrbg(entropy []byte, nonce []byte, personalization []byte) *hmacDrbg {
+	d := &hmacDrbg{
+		k: make([]byte, sha256.Size),
+		v: bytes.Repeat([]byte{0x01}, sha256.Size),
+	}
+	d.update(entropy, nonce, personalization)
+
+	return d
+}
+
+func (d *hmacDrbg) mac(data ...[]byte) []byte {
+	h := hmac.New(sha256.New, d.k)
+	for _, b := range data {
+		h.Write(b)
+	}
+	return h.Sum(nil)
+}
+
+// update is the HMAC_DRBG_Update function, over the concatenation of the
+// provided data.
+func (d *hmacDrbg) update(provided ...[]byte) {
+	d.k = d.mac(append([][]byte{d.v, {0x00}}, provided...)...)
+	d.v = d.mac(d.v)
+
+	if len(bytes.Join(provided, nil)) == 0 {
+		return
+	}
+	d.k = d.mac(append([][]byte{d.v, {0x01}}, provided...)...)
+	d.v = d.mac(d.v)
+}
+
+// Read fills p with the output of one generate request of the DRBG.
+func (d *hmacDrbg) Read(p []byte) (int, error) {
+	for n := 0; n < len(p); {
+		d.v = d.mac(d.v)
+		n += copy(p[n:], d.v)
+	}
+	d.update()
+
+	return len(p), nil
+}
+
+// uniformInt returns a uniformly random integer in [0, n) read from r, by
+// rejection sampling of big endian 64-bit values, so that ids derived from a
+// seed do not depend on the sampling of the standard library.
+func uniformInt(r io.Reader, n int) (int, error) {
+	m := uint64(n)
+	// The largest value whose remainder is not biased.
+	limit := uint64(math.MaxUint64) - (uint64(math.MaxUint64)%m+1)%m
+
+	var buf [8]byte
+	for {
+		if _, err := io.ReadFull(r, buf[:]); err != nil {
+			return 0, err
+		}
+		if v := binary.BigEndian.Uint64(buf[:]); v <= limit {
+			return int(v % m), nil
+		}
+	}
+}
+
+// seededRandom returns the stream of bytes from which the id of a resource is
+// derived when its seed is set, or nil to generate it randomly. The seed is
+// the entropy input of the DRBG, the salt its nonce and the canonical keepers
+// and sensitive keepers with the generation of the id its personalization
+// string, the seed and the salt prefixed with their lengths to keep them
+// apart. The generation keeps ids regenerated in place by changing the
+// keepers back to earlier values from repeating retired ids.
+func seededRandom(ctx context.Context, seed types.String, salt types.String, keepers types.Dynamic, keepersSensitive types.Dynamic, generation types.Int64) (io.Reader, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	if seed.IsNull() || seed.IsUnknown() {
+		return nil, diags
+	}
+
+	canonical := map[string]any{}
+	for name, keepers := range map[string]types.Dynamic{"keepers": keepers, "keepers_sensitive": keepersSensitive} {
+		value, err := keepers.ToTerraformValue(ctx)
+		if err != nil {
+			diags.AddError("Invalid keepers", fmt.Sprintf("Invalid %s: %s.", name, err))
+			return nil, diags
+		}
+		canonical[name], err = canonicalKeepers(value)
+		if err != nil {
+			diags.AddError("Invalid keepers", fmt.Sprintf("Invalid %s: %s.", name, err))
+			return nil, diags
+		}
+	}
+	// Ids without a generation yet are generated as the first one.
+	n := generation.ValueInt64()
+	if generation.IsNull() || generation.IsUnknown() {
+		n = 1
+	}
+	canonical["generation"] = n
+	personalization, err := json.Marshal(canonical)
+	if err != nil {
+		diags.AddError("Invalid keepers", fmt.Sprintf("Invalid keepers: %s.", err))
+		return nil, diags
+	}
+
+	return newHmacDrbg(lengthPrefixed(seed.ValueString()), lengthPrefixed(salt.ValueString()), personalization), diags
+}
+
+func lengthPrefixed(s string) []byte {
+	return append(binary.BigEndian.AppendUint32(nil, uint32(len(s))), s...)
+}
@@ -0,0 +1,116 @@
+// Copyright (c) The Nanoid Provider for Terraform Authors
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"encoding/hex"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// The expected values pin SEEDED_ID_ALGORITHM: ids derived from a seed must
+// never change without a new algorithm name.
+
+func TestHmacDrbg(t *testing.T) {
+	d := newHmacDrbg(lengthPrefixed("correct horse battery staple"), lengthPrefixed("eu-west-1"), []byte(`{"env":"prod"}`))
+
+	expected := []string{
+		"d6b98c152645f4fb545d34bfe6405086",
+		"40f02dff624557528360eee10fb0d6c1f2c08093708aa0bfebd0c02acfc07000159954cae06892de",
+	}
+	for _, e := range expected {
+		out := make([]byte, len(e)/2)
+		if _, err := d.Read(out); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		if hex.EncodeToString(out) != e {
+			t.Errorf("expected %s, got %x", e, out)
+		}
+	}
+}
+
+func TestUniformInt(t *testing.T) {
+	d := newHmacDrbg([]byte("seed"), nil, nil)
+	counts := make([]int, 7)
+	for i := 0; i < 7000; i++ {
+		n, err := uniformInt(d, len(counts))
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		counts[n]++
+	}
+	for n, c := range counts {
+		if c < 850 || c > 1150 {
+			t.Errorf("expected about 1000 draws of %d, got %d", n, c)
+		}
+	}
+}
+
+func TestSeededIds(t *testing.T) {
+	ctx := context.Background()
+	keepers := types.DynamicValue(types.ObjectValueMust(
+		map[string]attr.Type{"env": types.StringType},
+		map[string]attr.Value{"env": types.StringValue("prod")},
+	))
+
+	dns := DnsResourceModel{
+		Seed:       types.StringValue("correct horse battery staple"),
+		Salt:       types.StringValue("eu-west-1"),
+		Keepers:    keepers,
+		Generation: types.Int64Value(1),
+	}
+	if diags := dns.generateId(ctx); diags.HasError() {
+		t.Fatalf("unexpected error: %v", diags)
+	}
+	if dns.Id.ValueString() != "aup5uympce" {
+		t.Errorf("expected id aup5uympce, got %s", dns.Id.ValueString())
+	}
+	if dns.Algorithm.ValueString() != SEEDED_ID_ALGORITHM {
+		t.Errorf("expected algorithm %s, got %s", SEEDED_ID_ALGORITHM, dns.Algorithm.ValueString())
+	}
+
+	// The same keepers derive another id in a later generation.
+	dns.Generation = types.Int64Value(3)
+	if diags := dns.generateId(ctx); diags.HasError() {
+		t.Fatalf("unexpected error: %v", diags)
+	}
+	if dns.Id.ValueString() != "y8lirnjp1v" {
+		t.Errorf("expected id y8lirnjp1v, got %s", dns.Id.ValueString())
+	}
+
+	// Only the sensitive keepers change.
+	dns.Generation = types.Int64Value(1)
+	dns.KeepersSensitive = types.DynamicValue(types.ObjectValueMust(
+		map[string]attr.Type{"version": types.StringType},
+		map[string]attr.Value{"version": types.StringValue("2")},
+	))
+	if diags := dns.generateId(ctx); diags.HasError() {
+		t.Fatalf("unexpected error: %v", diags)
+	}
+	if dns.Id.ValueString() != "7uaswn11rb" {
+		t.Errorf("expected id 7uaswn11rb, got %s", dns.Id.ValueString())
+	}
+
+	id := IdResourceModel{
+		Seed:    types.StringValue("correct horse battery staple"),
+		Keepers: types.DynamicNull(),
+	}
+	if diags := id.generateId(ctx); diags.HasError() {
+		t.Fatalf("unexpected error: %v", diags)
+	}
+	if id.Id.ValueString() != "N2ZDyvp6g90GpK-547GyN" {
+		t.Errorf("expected id N2ZDyvp6g90GpK-547GyN, got %s", id.Id.ValueString())
+	}
+
+	unseeded := IdResourceModel{Keepers: types.DynamicNull()}
+	if diags := unseeded.generateId(ctx); diags.HasError() {
+		t.Fatalf("unexpected error: %v", diags)
+	}
+	if unseeded.Algorithm.ValueString() != ID_ALGORITHM {
+		t.Errorf("expected algorithm %s, got %s", ID_ALGORITHM, unseeded.Algorithm.ValueString())
+	}
+}
@@ -0,0 +1,178 @@
+// Copyright (c) The Nanoid Provider for Terraform Authors
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"math"
+	"slices"
+	"testing"
+)
+
+func TestSqids_KnownValues(t *testing.T) {
+	cases := []struct {
+		alphabet  string
+		minLength int
+		numbers   []uint64
+		expected  string
+	}{
+		{DEFAULT_SQIDS_ALPHABET, 0, []uint64{1, 2, 3}, "86Rf07"},
+		{DEFAULT_SQIDS_ALPHABET, 10, []uint64{1, 2, 3}, "86Rf07xd4z"},
+		{DEFAULT_SQIDS_ALPHABET, 0, []uint64{0}, "bM"},
+		{DEFAULT_SQIDS_ALPHABET, 0, []uint64{1}, "Uk"},
+		{"FxnXM1kBN6cuhsAvjW3Co7l2RePyY8DwaU04Tzt9fHQrqSVKdpimLGIJOgb5ZE", 0, []uint64{1, 2, 3}, "B4aajs"},
+	}
+
+	for _, c := range cases {
+		s, err := newSqids(c.alphabet, c.minLength)
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		id, err := s.encode(c.numbers)
+		if err != nil {
+			t.Fatalf("encode(%v): unexpected error: %s", c.numbers, err)
+		}
+		if id != c.expected {
+			t.Errorf("encode(%v): expected %q, got %q", c.numbers, c.expected, id)
+		}
+		numbers, err := s.decode(id)
+		if err != nil {
+			t.Fatalf("decode(%q): unexpected error: %s", id, err)
+		}
+		if !slices.Equal(numbers, c.numbers) {
+			t.Errorf("decode(%q): expected %v, got %v", id, c.numbers, numbers)
+		}
+	}
+}
+
+func TestSqids_RoundTrip(t *testing.T) {
+	for _, alphabet := range []string{DEFAULT_SQIDS_ALPHABET, "abc", "0123456789"} {
+		for _, minLength := range []int{0, 5, MAX_SQIDS_MIN_LENGTH} {
+			s, err := newSqids(alphabet, minLength)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			for _, numbers := range [][]uint64{{0}, {42, 0, 7}, {math.MaxUint64}, {1, 2, 3, 4, 5, 6, 7, 8, 9, 10}} {
+				id, err := s.encode(numbers)
+				if err != nil {
+					t.Fatalf("encode(%v): unexpected error: %s", numbers, err)
+				}
+				if len(id) < minLength {
+					t.Errorf("encode(%v): expected at least %d characters, got %q", numbers, minLength, id)
+				}
+				decoded, err := s.decode(id)
+				if err != nil {
+					t.Fatalf("decode(%q): unexpected error: %s", id, err)
+				}
+				if !slices.Equal(decoded, numbers) {
+					t.Errorf("decode(%q): expected %v, got %v", id, numbers, decoded)
+				}
+			}
+		}
+	}
+}
+
+func TestSqids_Blocklist(t *testing.T) {
+	s, err := newSqids(DEFAULT_SQIDS_ALPHABET, 0)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	for n := uint64(0); n < 100000; n++ {
+		id, err := s.encode([]uint64{n})
+		if err != nil {
+			t.Fatalf("encode(%d): unexpected error: %s", n, err)
+		}
+		if s.isBlocked(id) {
+			t.Fatalf("encode(%d): the id %q is blocked", n, id)
+		}
+	}
+}
+
+// The cases of the blocklist tests of the Sqids specification, see
+// https://github.com/sqids/sqids-spec/blob/main/tests/blocklist.test.ts.
+func TestSqids_BlocklistSpec(t *testing.T) {
+	cases := []struct {
+		name      string
+		alphabet  string
+		minLength int
+		blocklist []string
+		numbers   []uint64
+		expected  string
+		blocked   []string
+	}{
+		{"default", DEFAULT_SQIDS_ALPHABET, 0, SQIDS_BLOCKLIST, []uint64{4572721}, "JExTR", []string{"aho1e"}},
+		{"empty", DEFAULT_SQIDS_ALPHABET, 0, []string{}, []uint64{4572721}, "aho1e", nil},
+		{"non-empty", DEFAULT_SQIDS_ALPHABET, 0, []string{"ArUO"}, []uint64{100000}, "QyG4", []string{"ArUO"}},
+		{"non-empty without default", DEFAULT_SQIDS_ALPHABET, 0, []string{"ArUO"}, []uint64{4572721}, "aho1e", nil},
+		{"new blocklist", DEFAULT_SQIDS_ALPHABET, 0, []string{"JSwXFaosAN", "OCjV9JK64o", "rBHf", "79SM", "7tE6"},
+			[]uint64{1000000, 2000000}, "1aYeB7bRUt", nil},
+		{"decoding blocked ids", DEFAULT_SQIDS_ALPHABET, 0, []string{"86Rf07", "se8ojk", "ARsz1p", "Q8AI49", "5sQRZO"},
+			[]uint64{1, 2, 3}, "", []string{"86Rf07", "se8ojk", "ARsz1p", "Q8AI49", "5sQRZO"}},
+		{"uppercase alphabet", "ABCDEFGHIJKLMNOPQRSTUVWXYZ", 0, []string{"sxnzkl"}, []uint64{1, 2, 3}, "IBSHOZ", nil},
+	}
+
+	for _, c := range cases {
+		s, err := newSqidsWithBlocklist(c.alphabet, c.minLength, c.blocklist)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %s", c.name, err)
+		}
+		id, err := s.encode(c.numbers)
+		if err != nil {
+			t.Fatalf("%s: encode(%v): unexpected error: %s", c.name, c.numbers, err)
+		}
+		if c.expected != "" && id != c.expected {
+			t.Errorf("%s: encode(%v): expected %q, got %q", c.name, c.numbers, c.expected, id)
+		}
+		for _, blocked := range append([]string{id}, c.blocked...) {
+			numbers, err := s.decode(blocked)
+			if err != nil {
+				t.Fatalf("%s: decode(%q): unexpected error: %s", c.name, blocked, err)
+			}
+			if !slices.Equal(numbers, c.numbers) {
+				t.Errorf("%s: decode(%q): expected %v, got %v", c.name, blocked, c.numbers, numbers)
+			}
+		}
+	}
+
+	s, err := newSqidsWithBlocklist("abc", 3, []string{"cab", "abc", "bca"})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if _, err := s.encode([]uint64{0}); err == nil {
+		t.Errorf("expected an error once every id is blocked")
+	}
+
+	s, err = newSqidsWithBlocklist(DEFAULT_SQIDS_ALPHABET, 0, []string{"pnd"})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	id, err := s.encode([]uint64{1000})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if numbers, err := s.decode(id); err != nil || !slices.Equal(numbers, []uint64{1000}) {
+		t.Errorf("decode(%q): expected [1000], got %v, %v", id, numbers, err)
+	}
+}
+
+func TestSqids_Errors(t *testing.T) {
+	for _, alphabet := range []string{"ab", "aba", "abcé"} {
+		if _, err := newSqids(alphabet, 0); err == nil {
+			t.Errorf("newSqids(%q): expected an error", alphabet)
+		}
+	}
+	if _, err := newSqids(DEFAULT_SQIDS_ALPHABET, MAX_SQIDS_MIN_LENGTH+1); err == nil {
+		t.Errorf("expected an error for a minimum length over %d", MAX_SQIDS_MIN_LENGTH)
+	}
+
+	s, err := newSqids(DEFAULT_SQIDS_ALPHABET, 0)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if _, err := s.decode("86Rf-07"); err == nil {
+		t.Errorf("expected an error for a character outside of the alphabet")
+	}
+	if _, err := s.decode("8" + "zzzzzzzzzzzzzzzzzzzzzzzzzzzz"); err == nil {
+		t.Errorf("expected an error for a number that is too large")
+	}
+}
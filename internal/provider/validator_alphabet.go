@@ -0,0 +1,111 @@
+// Copyright (c) The Nanoid Provider for Terraform Authors
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"golang.org/x/text/unicode/norm"
+)
+
+// HOMOGLYPHS maps characters of other scripts to the Latin character or digit
+// they cannot be told apart from.
+var HOMOGLYPHS = map[rune]rune{
+	// Cyrillic
+	'а': 'a', 'в': 'B', 'е': 'e', 'к': 'K', 'м': 'M', 'н': 'H', 'о': 'o', 'р': 'p', 'с': 'c', 'т': 'T', 'у': 'y', 'х': 'x',
+	'А': 'A', 'В': 'B', 'Е': 'E', 'К': 'K', 'М': 'M', 'Н': 'H', 'О': 'O', 'Р': 'P', 'С': 'C', 'Т': 'T', 'Х': 'X',
+	'і': 'i', 'ј': 'j', 'ѕ': 's', 'ԁ': 'd', 'ԛ': 'q', 'ԝ': 'w', 'І': 'I', 'Ј': 'J', 'Ѕ': 'S', 'З': '3', 'б': '6',
+	// Greek
+	'Α': 'A', 'Β': 'B', 'Ε': 'E', 'Ζ': 'Z', 'Η': 'H', 'Ι': 'I', 'Κ': 'K', 'Μ': 'M', 'Ν': 'N', 'Ο': 'O', 'Ρ': 'P',
+	'Τ': 'T', 'Υ': 'Y', 'Χ': 'X', 'ο': 'o', 'ν': 'v', 'ι': 'i', 'κ': 'k', 'ρ': 'p', 'υ': 'u',
+	// Fullwidth and other lookalikes
+	'０': '0', '１': '1', 'ｌ': 'l', 'Ｉ': 'I', 'Ｏ': 'O', 'ℓ': 'l', 'ǀ': '|', '∣': '|', '‐': '-', '‑': '-', '−': '-', '＿': '_',
+}
+
+var _ validator.String = alphabetValidator{}
+
+// alphabetValidator validates that the characters of an alphabet are
+// distinct, visible and normalized.
+type alphabetValidator struct{}
+
+func (v alphabetValidator) Description(ctx context.Context) string {
+	return "value must not contain duplicate, whitespace, control or unnormalized characters"
+}
+
+func (v alphabetValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v alphabetValidator) ValidateString(ctx context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+
+	runes := []rune(req.ConfigValue.ValueString())
+	positions := map[rune][]int{}
+	var duplicates []rune
+	for i, r := range runes {
+		if len(positions[r]) == 1 {
+			duplicates = append(duplicates, r)
+		}
+		positions[r] = append(positions[r], i)
+	}
+	for _, r := range duplicates {
+		resp.Diagnostics.AddAttributeError(req.Path, "Duplicate alphabet character",
+			fmt.Sprintf("The character %q appears at positions %s, which makes it more likely than the other characters.",
+				r, joinPositions(positions[r])))
+	}
+
+	for i, r := range runes {
+		switch {
+		case unicode.IsSpace(r):
+			resp.Diagnostics.AddAttributeError(req.Path, "Invalid alphabet character",
+				fmt.Sprintf("The character %U at position %d is whitespace.", r, i))
+		case unicode.IsControl(r) || unicode.Is(unicode.Cf, r):
+			resp.Diagnostics.AddAttributeError(req.Path, "Invalid alphabet character",
+				fmt.Sprintf("The character %U at position %d is a control or formatting character.", r, i))
+		case unicode.Is(unicode.M, r):
+			resp.Diagnostics.AddAttributeError(req.Path, "Unnormalized alphabet character",
+				fmt.Sprintf("The character %U at position %d is a combining mark, which only exists in decomposed forms. "+
+					"Use the precomposed character instead.", r, i))
+		case norm.NFC.String(string(r)) != string(r):
+			normalized := norm.NFC.String(string(r))
+			codes := make([]string, 0, len(normalized))
+			for _, n := range normalized {
+				codes = append(codes, fmt.Sprintf("%U", n))
+			}
+			resp.Diagnostics.AddAttributeError(req.Path, "Unnormalized alphabet character",
+				fmt.Sprintf("The character %U at position %d is not in Unicode normalization form C, use %q (%s) instead.",
+					r, i, normalized, strings.Join(codes, " ")))
+		}
+
+		if lookalike, ok := HOMOGLYPHS[r]; ok {
+			resp.Diagnostics.AddAttributeWarning(req.Path, "Confusable alphabet character",
+				fmt.Sprintf("The character %q (%U) at position %d cannot be told apart from %q.", r, r, i, lookalike))
+		}
+	}
+}
+
+// validAlphabet returns a validator which ensures that the characters of an
+// alphabet are distinct, visible and normalized, and warns about homoglyphs.
+func validAlphabet() validator.String {
+	return alphabetValidator{}
+}
+
+// joinPositions formats positions as "1, 2 and 3".
+func joinPositions(positions []int) string {
+	parts := make([]string, len(positions))
+	for i, p := range positions {
+		parts[i] = fmt.Sprint(p)
+	}
+	if len(parts) == 1 {
+		return parts[0]
+	}
+
+	return strings.Join(parts[:len(parts)-1], ", ") + " and " + parts[len(parts)-1]
+}
@@ -0,0 +1,58 @@
+// Copyright (c) The Nanoid Provider for Terraform Authors
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestAlphabetValidator(t *testing.T) {
+	cases := []struct {
+		alphabet string
+		errors   []string
+		warnings []string
+	}{
+		{alphabet: DEFAULT_ID_ALPHABET},
+		{alphabet: "0123456789abcdef"},
+		{alphabet: "αβγδé"},
+		{alphabet: "abc-de-f-", errors: []string{`The character '-' appears at positions 3, 6 and 8`}},
+		{alphabet: "abab", errors: []string{`The character 'a' appears at positions 0 and 2`, `The character 'b' appears at positions 1 and 3`}},
+		{alphabet: "ab c", errors: []string{`The character U+0020 at position 2 is whitespace`}},
+		{alphabet: "ab\tc", errors: []string{`The character U+0009 at position 2 is whitespace`}},
+		{alphabet: "ab\x00c", errors: []string{`The character U+0000 at position 2 is a control or formatting character`}},
+		{alphabet: "ab‍c", errors: []string{`The character U+200D at position 2 is a control or formatting character`}},
+		{alphabet: "abé", errors: []string{`The character U+0301 at position 3 is a combining mark`}},
+		{alphabet: "abÅ", errors: []string{`The character U+212B at position 2 is not in Unicode normalization form C, use "Å" (U+00C5) instead`}},
+		{alphabet: "abcа", warnings: []string{`The character 'а' (U+0430) at position 3 cannot be told apart from 'a'`}},
+		{alphabet: "01Ο", warnings: []string{`The character 'Ο' (U+039F) at position 2 cannot be told apart from 'O'`}},
+	}
+
+	for _, c := range cases {
+		req := validator.StringRequest{Path: path.Root("alphabet"), ConfigValue: types.StringValue(c.alphabet)}
+		resp := &validator.StringResponse{}
+		validAlphabet().ValidateString(context.Background(), req, resp)
+
+		errors, warnings := resp.Diagnostics.Errors(), resp.Diagnostics.Warnings()
+		if len(errors) != len(c.errors) || len(warnings) != len(c.warnings) {
+			t.Errorf("%q: expected %d errors and %d warnings, got %v", c.alphabet, len(c.errors), len(c.warnings), resp.Diagnostics)
+			continue
+		}
+		for i, expected := range c.errors {
+			if !strings.Contains(errors[i].Detail(), expected) {
+				t.Errorf("%q: expected error %q, got %q", c.alphabet, expected, errors[i].Detail())
+			}
+		}
+		for i, expected := range c.warnings {
+			if !strings.Contains(warnings[i].Detail(), expected) {
+				t.Errorf("%q: expected warning %q, got %q", c.alphabet, expected, warnings[i].Detail())
+			}
+		}
+	}
+}
@@ -0,0 +1,56 @@
+// Copyright (c) The Nanoid Provider for Terraform Authors
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+)
+
+var _ validator.String = formatValidator{}
+
+// formatValidator validates that a format only uses known placeholders and
+// renders the id exactly once.
+type formatValidator struct {
+	placeholders []string
+}
+
+func (v formatValidator) Description(ctx context.Context) string {
+	return fmt.Sprintf("value must contain {id} once and no placeholders other than {%s}", strings.Join(v.placeholders, "}, {"))
+}
+
+func (v formatValidator) MarkdownDescription(ctx context.Context) string {
+	return fmt.Sprintf("value must contain `{id}` once and no placeholders other than `{%s}`", strings.Join(v.placeholders, "}`, `{"))
+}
+
+func (v formatValidator) ValidateString(ctx context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+
+	ids := 0
+	for _, name := range formatPlaceholders(req.ConfigValue.ValueString()) {
+		if name == "id" {
+			ids++
+		}
+		if !slices.Contains(v.placeholders, name) {
+			resp.Diagnostics.AddAttributeError(req.Path, "Invalid format",
+				fmt.Sprintf("The placeholder {%s} is unknown, use one of {%s}.", name, strings.Join(v.placeholders, "}, {")))
+		}
+	}
+	if ids != 1 {
+		resp.Diagnostics.AddAttributeError(req.Path, "Invalid format",
+			fmt.Sprintf("The format must contain the placeholder {id} once, found %d times.", ids))
+	}
+}
+
+// isFormat returns a validator which ensures that a format contains the {id}
+// placeholder once and no placeholders other than the given ones.
+func isFormat(placeholders []string) validator.String {
+	return formatValidator{placeholders: placeholders}
+}
@@ -0,0 +1,78 @@
+// Copyright (c) The Nanoid Provider for Terraform Authors
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// entropyModel is implemented by the data models of resources generating ids
+// with a min_entropy_bits attribute.
+type entropyModel interface {
+	// minEntropyBits returns the configured minimum entropy.
+	minEntropyBits() types.Float64
+	// configuredPositions returns the alphabets and length of the configured
+	// ids, or false when they are not known yet.
+	configuredPositions() (*positionalAlphabets, int, bool)
+}
+
+var _ resource.ConfigValidator = minEntropyValidator{}
+
+// minEntropyValidator validates that the alphabets and length of a resource
+// generate ids with at least min_entropy_bits of entropy.
+type minEntropyValidator struct {
+	newModel func() entropyModel
+}
+
+func (v minEntropyValidator) Description(ctx context.Context) string {
+	return "the entropy of the generated ids must be at least min_entropy_bits"
+}
+
+func (v minEntropyValidator) MarkdownDescription(ctx context.Context) string {
+	return "the entropy of the generated ids must be at least `min_entropy_bits`"
+}
+
+func (v minEntropyValidator) ValidateResource(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	data := v.newModel()
+	resp.Diagnostics.Append(req.Config.Get(ctx, data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	minBits := data.minEntropyBits()
+	if minBits.IsNull() || minBits.IsUnknown() {
+		return
+	}
+	positions, length, ok := data.configuredPositions()
+	if !ok {
+		return
+	}
+
+	bits := positions.entropyBits(length)
+	if bits >= minBits.ValueFloat64() {
+		return
+	}
+
+	advice := fmt.Sprintf("No length up to %d reaches it, use a larger alphabet.", DEFAULT_MAX_ID_LENGTH)
+	for l := length + 1; l <= DEFAULT_MAX_ID_LENGTH; l++ {
+		if positions.entropyBits(l) >= minBits.ValueFloat64() {
+			advice = fmt.Sprintf("Set length to at least %d or use a larger alphabet.", l)
+			break
+		}
+	}
+	resp.Diagnostics.AddAttributeError(path.Root("min_entropy_bits"), "Insufficient entropy",
+		fmt.Sprintf("The generated ids would have %.2f bits of entropy, below min_entropy_bits of %g. %s",
+			bits, minBits.ValueFloat64(), advice))
+}
+
+// minEntropyBits returns a resource validator which ensures that the
+// configured ids have at least min_entropy_bits of entropy.
+func minEntropyBits(newModel func() entropyModel) resource.ConfigValidator {
+	return minEntropyValidator{newModel: newModel}
+}
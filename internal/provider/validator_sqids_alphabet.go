@@ -0,0 +1,41 @@
+// Copyright (c) The Nanoid Provider for Terraform Authors
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+)
+
+var _ validator.String = sqidsAlphabetValidator{}
+
+// sqidsAlphabetValidator validates that a string is a Sqids alphabet.
+type sqidsAlphabetValidator struct{}
+
+func (v sqidsAlphabetValidator) Description(ctx context.Context) string {
+	return "value must contain at least 3 distinct ASCII characters"
+}
+
+func (v sqidsAlphabetValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v sqidsAlphabetValidator) ValidateString(ctx context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+
+	if err := validateSqidsAlphabet(req.ConfigValue.ValueString()); err != nil {
+		resp.Diagnostics.AddAttributeError(req.Path, "Invalid alphabet",
+			fmt.Sprintf("Invalid alphabet: %s.", err))
+	}
+}
+
+// isSqidsAlphabet returns a validator which ensures that a string is a Sqids
+// alphabet.
+func isSqidsAlphabet() validator.String {
+	return sqidsAlphabetValidator{}
+}